@@ -17,6 +17,9 @@
 package build
 
 import (
+	"os"
+	"path/filepath"
+
 	"github.com/buildpacks/libbuildpack/v2/build"
 	bp "github.com/buildpacks/libbuildpack/v2/layers"
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
@@ -27,6 +30,10 @@ import (
 	"github.com/cloudfoundry/libcfbuildpack/v2/services"
 )
 
+// LayerStatistics is the environment variable that, when set, specifies the file that layer statistics are written to
+// as JSON at the end of a successful build.
+const LayerStatistics = "BP_LAYER_STATISTICS"
+
 // Build is an extension to libbuildpack.Build that allows additional functionality to be added.
 type Build struct {
 	build.Build
@@ -48,10 +55,15 @@ type Build struct {
 
 	// Services represents the services bound to the application.
 	Services services.Services
+
+	// Configuration resolves the configurations declared by the buildpack.  The effective configuration is logged at the
+	// start of Phases.Run.
+	Configuration buildpack.ConfigurationResolver
 }
 
 // Success signals a successful build by exiting with a zero status code.  Combines specied build plan with build
-// plan entries for all contributed dependencies.
+// plan entries for all contributed dependencies.  The contributed dependencies are also written as a bill-of-materials
+// and a summary of the time and space used by contributed layers is printed.
 func (b Build) Success(plans ...buildpackplan.Plan) (int, error) {
 
 	code, err := b.Build.Success(append(b.Layers.Plans.Entries, plans...)...)
@@ -59,10 +71,19 @@ func (b Build) Success(plans ...buildpackplan.Plan) (int, error) {
 		return code, err
 	}
 
+	if err := b.Layers.WriteBillOfMaterials(); err != nil {
+		return -1, err
+	}
+
 	if err := b.Layers.TouchedLayers.Cleanup(); err != nil {
 		return -1, err
 	}
 
+	b.Layers.Statistics.Log(b.Logger)
+	if err := b.Layers.Statistics.Write(); err != nil {
+		return -1, err
+	}
+
 	return code, nil
 }
 
@@ -76,10 +97,19 @@ func DefaultBuild() (Build, error) {
 
 	logger := logger.Logger{Logger: b.Logger}
 	buildpack := buildpack.NewBuildpack(b.Buildpack, logger)
+	buildpack.BuildpackYAML = filepath.Join(b.Application.Root, "buildpack.yml")
 	layers := layers.NewLayers(b.Layers, bp.NewLayers(buildpack.CacheRoot, b.Logger), buildpack, logger)
+	if f, ok := os.LookupEnv(LayerStatistics); ok {
+		layers.Statistics.File = f
+	}
 	plans := buildpackplan.Plans{Plans: b.Plans}
 	services := services.Services{Services: b.Services}
 
+	configuration, err := buildpack.ConfigurationResolver()
+	if err != nil {
+		return Build{}, err
+	}
+
 	return Build{
 		b,
 		buildpack,
@@ -88,5 +118,6 @@ func DefaultBuild() (Build, error) {
 		plans,
 		runner.CommandRunner{},
 		services,
+		configuration,
 	}, nil
 }
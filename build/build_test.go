@@ -23,6 +23,7 @@ import (
 	"github.com/buildpacks/libbuildpack/v2/buildpackplan"
 	"github.com/cloudfoundry/libcfbuildpack/v2/build"
 	"github.com/cloudfoundry/libcfbuildpack/v2/internal"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
 	"github.com/cloudfoundry/libcfbuildpack/v2/test"
 	"github.com/onsi/gomega"
 	"github.com/sclevine/spec"
@@ -123,6 +124,27 @@ test-key = "test-value"
 `))
 		})
 
+		it("writes layer statistics when successful", func() {
+			defer internal.ReplaceWorkingDirectory(t, root)()
+			defer test.ReplaceEnv(t, "CNB_STACK_ID", "test-stack")()
+			defer test.ReplaceEnv(t, build.LayerStatistics, filepath.Join(root, "statistics.json"))()
+			defer internal.ReplaceArgs(t, filepath.Join(root, "bin", "test"), filepath.Join(root, "layers"), filepath.Join(root, "platform"), filepath.Join(root, "plan.toml"))()
+
+			test.TouchFile(t, root, "buildpack.toml")
+			test.TouchFile(t, root, "plan.toml")
+
+			b, err := build.DefaultBuild()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(b.Layers.Layer("test-layer").Contribute(nil, func(layer layers.Layer) error {
+				return nil
+			})).To(gomega.Succeed())
+
+			g.Expect(b.Success()).To(gomega.Equal(build.SuccessStatusCode))
+
+			g.Expect(filepath.Join(root, "statistics.json")).To(gomega.BeARegularFile())
+		})
+
 		it("returns code when failing", func() {
 			defer internal.ReplaceWorkingDirectory(t, root)()
 			defer test.ReplaceEnv(t, "CNB_STACK_ID", "test-stack")()
@@ -0,0 +1,228 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package build
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+)
+
+// FailureStatusCode is the status code returned when a phase fails.
+const FailureStatusCode = 1
+
+// Contributor contributes part of a build.
+type Contributor func() error
+
+// Phase is a named Contributor that runs after the phases it depends on.
+type Phase struct {
+	// Name is the name of the phase.
+	Name string
+
+	// Contributor contributes the phase.
+	Contributor Contributor
+
+	// DependsOn are the names of the phases that must complete before this phase runs.
+	DependsOn []string
+}
+
+// Timing is the time taken by a phase.
+type Timing struct {
+	// Name is the name of the phase.
+	Name string
+
+	// Duration is the time the phase took.
+	Duration time.Duration
+}
+
+// Phases runs Phase's in dependency order and signals the success or failure of the build.
+type Phases struct {
+	// Parallel indicates whether phases that do not depend on one another run concurrently.
+	Parallel bool
+
+	build   Build
+	phases  []Phase
+	timings []Timing
+}
+
+// Phases returns an empty collection of phases for the build.
+func (b Build) Phases() *Phases {
+	return &Phases{build: b}
+}
+
+// Add adds a phase that runs after the phases it depends on.
+func (p *Phases) Add(name string, contributor Contributor, dependsOn ...string) *Phases {
+	p.phases = append(p.phases, Phase{Name: name, Contributor: contributor, DependsOn: dependsOn})
+	return p
+}
+
+// Run logs the effective configuration of the buildpack and then runs the phases in dependency order.  If every phase
+// succeeds, the time taken by each phase is logged and Build.Success is called with the plans.  If a phase fails, or
+// the phases cannot be ordered, the error is logged as a terminal error and Build.Failure is called with the
+// FailureStatusCode.  Phases that depend on a failed phase do not run.  When phases run in parallel, every phase of a
+// level runs to completion and the errors of all failed phases are returned as layers.ContributionErrors.
+func (p *Phases) Run(plans ...buildpackplan.Plan) (int, error) {
+	p.build.Configuration.Log(p.build.Logger)
+	p.timings = nil
+
+	levels, err := p.levels()
+	if err == nil {
+		for _, level := range levels {
+			if err = p.runLevel(level); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		p.build.Logger.TerminalError(p.build.Buildpack, "%s", err.Error())
+		return p.build.Failure(FailureStatusCode), err
+	}
+
+	p.logTimings()
+	return p.build.Success(plans...)
+}
+
+// Timings returns the time taken by each phase that completed in the last Run, in the order they completed.
+func (p *Phases) Timings() []Timing {
+	return p.timings
+}
+
+// levels groups the phases so that every phase is in a later level than the phases it depends on.
+func (p *Phases) levels() ([][]Phase, error) {
+	phases := make(map[string]Phase, len(p.phases))
+	for _, ph := range p.phases {
+		if _, ok := phases[ph.Name]; ok {
+			return nil, fmt.Errorf("phase %s is declared more than once", ph.Name)
+		}
+		phases[ph.Name] = ph
+	}
+
+	remaining := make(map[string]int, len(p.phases))
+	for _, ph := range p.phases {
+		for _, d := range ph.DependsOn {
+			if _, ok := phases[d]; !ok {
+				return nil, fmt.Errorf("phase %s depends on unknown phase %s", ph.Name, d)
+			}
+		}
+		remaining[ph.Name] = len(ph.DependsOn)
+	}
+
+	var levels [][]Phase
+	done := make(map[string]bool, len(p.phases))
+
+	for len(done) < len(p.phases) {
+		var level []Phase
+		for _, ph := range p.phases {
+			if !done[ph.Name] && remaining[ph.Name] == 0 {
+				level = append(level, ph)
+			}
+		}
+
+		if len(level) == 0 {
+			var cycle []string
+			for _, ph := range p.phases {
+				if !done[ph.Name] {
+					cycle = append(cycle, ph.Name)
+				}
+			}
+			sort.Strings(cycle)
+			return nil, fmt.Errorf("phases %s have circular dependencies", strings.Join(cycle, ", "))
+		}
+
+		for _, ph := range level {
+			done[ph.Name] = true
+		}
+
+		for _, ph := range p.phases {
+			for _, d := range ph.DependsOn {
+				for _, l := range level {
+					if d == l.Name {
+						remaining[ph.Name]--
+					}
+				}
+			}
+		}
+
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+func (p *Phases) runLevel(level []Phase) error {
+	if !p.Parallel || len(level) == 1 {
+		for _, ph := range level {
+			t, err := p.run(ph)
+			if err != nil {
+				return err
+			}
+			p.timings = append(p.timings, t)
+		}
+
+		return nil
+	}
+
+	var (
+		contributors = make([]func() error, len(level))
+		timings      = make([]Timing, len(level))
+	)
+
+	for i, ph := range level {
+		i, ph := i, ph
+		contributors[i] = func() (err error) {
+			timings[i], err = p.run(ph)
+			return err
+		}
+	}
+
+	err := layers.ContributeConcurrently(contributors...)
+
+	for _, t := range timings {
+		if t.Name != "" {
+			p.timings = append(p.timings, t)
+		}
+	}
+
+	return err
+}
+
+func (p *Phases) run(phase Phase) (Timing, error) {
+	p.build.Logger.Debug("Running phase %s", phase.Name)
+
+	start := time.Now()
+	if err := phase.Contributor(); err != nil {
+		return Timing{}, fmt.Errorf("phase %s failed: %s", phase.Name, err.Error())
+	}
+
+	return Timing{Name: phase.Name, Duration: time.Since(start)}, nil
+}
+
+func (p *Phases) logTimings() {
+	if len(p.timings) == 0 {
+		return
+	}
+
+	p.build.Logger.Header("Phase timing:")
+	for _, t := range p.timings {
+		p.build.Logger.Body("%s: %s", t.Name, t.Duration.Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package build_test
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/buildpacks/libbuildpack/v2/buildpackplan"
+	loggerBp "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestPhases(t *testing.T) {
+	spec.Run(t, "Phases", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			f     *test.BuildFactory
+			info  bytes.Buffer
+			mutex sync.Mutex
+			order []string
+		)
+
+		record := func(name string) build.Contributor {
+			return func() error {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				order = append(order, name)
+				return nil
+			}
+		}
+
+		it.Before(func() {
+			info.Reset()
+			order = nil
+
+			f = test.NewBuildFactory(t)
+			f.Build.Buildpack.Info.Name = "test-name"
+			f.Build.Logger = logger.Logger{Logger: loggerBp.NewLogger(nil, &info)}
+		})
+
+		it("runs phases in dependency order", func() {
+			g.Expect(f.Build.Phases().
+				Add("launch", record("launch"), "compile", "dependencies").
+				Add("compile", record("compile"), "dependencies").
+				Add("dependencies", record("dependencies")).
+				Run(buildpackplan.Plan{Name: "test-plan"})).To(gomega.Equal(build.SuccessStatusCode))
+
+			g.Expect(order).To(gomega.Equal([]string{"dependencies", "compile", "launch"}))
+			g.Expect(f.Plans.Entries).To(gomega.ContainElement(buildpackplan.Plan{Name: "test-plan"}))
+		})
+
+		it("runs independent phases in parallel", func() {
+			var barrier sync.WaitGroup
+			barrier.Add(2)
+
+			meet := func(name string) build.Contributor {
+				return func() error {
+					barrier.Done()
+
+					met := make(chan struct{})
+					go func() {
+						barrier.Wait()
+						close(met)
+					}()
+
+					select {
+					case <-met:
+						return record(name)()
+					case <-time.After(5 * time.Second):
+						return fmt.Errorf("%s did not run concurrently", name)
+					}
+				}
+			}
+
+			p := f.Build.Phases()
+			p.Parallel = true
+
+			g.Expect(p.
+				Add("alpha", meet("alpha")).
+				Add("bravo", meet("bravo")).
+				Add("charlie", record("charlie"), "alpha", "bravo").
+				Run()).To(gomega.Equal(build.SuccessStatusCode))
+
+			g.Expect(order).To(gomega.HaveLen(3))
+			g.Expect(order[2]).To(gomega.Equal("charlie"))
+		})
+
+		it("logs configuration at build start", func() {
+			f.Build.Configuration = buildpack.ConfigurationResolver{Configurations: []buildpack.Configuration{
+				{Name: "BP_TEST_KEY", Default: "test-default", Description: "test-description", Build: true},
+			}}
+
+			g.Expect(f.Build.Phases().Add("alpha", record("alpha")).Run()).To(gomega.Equal(build.SuccessStatusCode))
+
+			g.Expect(info.String()).To(gomega.ContainSubstring("Build configuration:"))
+			g.Expect(info.String()).To(gomega.ContainSubstring("$BP_TEST_KEY=test-default: test-description"))
+		})
+
+		it("collects and logs timings", func() {
+			p := f.Build.Phases().Add("alpha", record("alpha")).Add("bravo", record("bravo"), "alpha")
+
+			g.Expect(p.Run()).To(gomega.Equal(build.SuccessStatusCode))
+
+			g.Expect(p.Timings()).To(gomega.HaveLen(2))
+			g.Expect(p.Timings()[0].Name).To(gomega.Equal("alpha"))
+			g.Expect(info.String()).To(gomega.ContainSubstring("Phase timing:"))
+		})
+
+		it("fails without running dependent phases", func() {
+			code, err := f.Build.Phases().
+				Add("alpha", func() error { return fmt.Errorf("test-error") }).
+				Add("bravo", record("bravo"), "alpha").
+				Run()
+
+			g.Expect(code).To(gomega.Equal(build.FailureStatusCode))
+			g.Expect(err).To(gomega.MatchError("phase alpha failed: test-error"))
+			g.Expect(order).To(gomega.BeEmpty())
+			g.Expect(info.String()).To(gomega.ContainSubstring("phase alpha failed: test-error"))
+		})
+
+		it("fails with the errors of all failed parallel phases", func() {
+			p := f.Build.Phases()
+			p.Parallel = true
+
+			code, err := p.
+				Add("alpha", func() error { return fmt.Errorf("test-error-alpha") }).
+				Add("bravo", func() error { return fmt.Errorf("test-error-bravo") }).
+				Add("charlie", record("charlie")).
+				Run()
+
+			g.Expect(code).To(gomega.Equal(build.FailureStatusCode))
+			g.Expect(err).To(gomega.MatchError("phase alpha failed: test-error-alpha\nphase bravo failed: test-error-bravo"))
+			g.Expect(order).To(gomega.Equal([]string{"charlie"}))
+			g.Expect(p.Timings()).To(gomega.HaveLen(1))
+		})
+
+		it("fails with unknown dependency", func() {
+			_, err := f.Build.Phases().Add("alpha", record("alpha"), "bravo").Run()
+
+			g.Expect(err).To(gomega.MatchError("phase alpha depends on unknown phase bravo"))
+		})
+
+		it("fails with duplicate phase", func() {
+			_, err := f.Build.Phases().Add("alpha", record("alpha")).Add("alpha", record("alpha")).Run()
+
+			g.Expect(err).To(gomega.MatchError("phase alpha is declared more than once"))
+		})
+
+		it("fails with circular dependencies", func() {
+			_, err := f.Build.Phases().
+				Add("alpha", record("alpha"), "bravo").
+				Add("bravo", record("bravo"), "alpha").
+				Add("charlie", record("charlie")).
+				Run()
+
+			g.Expect(err).To(gomega.MatchError("phases alpha, bravo have circular dependencies"))
+			g.Expect(order).To(gomega.BeEmpty())
+		})
+	}, spec.Report(report.Terminal{}))
+}
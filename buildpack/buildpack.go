@@ -30,6 +30,8 @@ const (
 	CacheRoot            = "dependency-cache"
 	DependenciesMetadata = "dependencies"
 	DefaultVersions      = "default-versions"
+	StackParents         = "stack-parents"
+	VersionAliases       = "version-aliases"
 )
 
 // Buildpack is an extension to libbuildpack.Buildpack that adds additional opinionated behaviors.
@@ -39,21 +41,30 @@ type Buildpack struct {
 	// CacheRoot is the path to the root directory for the buildpack's dependency cache.
 	CacheRoot string
 
+	// BuildpackYAML is the path to the application's buildpack.yml, which may override default versions.  If empty, no
+	// buildpack.yml is consulted.
+	BuildpackYAML string
+
 	logger logger.Logger
 }
 
 // NewBuildpack creates a new instance of Buildpack from a specified buildpack.Buildpack.
 func NewBuildpack(buildpack buildpack.Buildpack, logger logger.Logger) Buildpack {
-	return Buildpack{buildpack, filepath.Join(buildpack.Root, CacheRoot), logger}
+	return Buildpack{Buildpack: buildpack, CacheRoot: filepath.Join(buildpack.Root, CacheRoot), logger: logger}
 }
 
 // Dependencies returns the collection of dependencies extracted from the generic buildpack metadata.
 func (b Buildpack) Dependencies() (Dependencies, error) {
-	deps, ok := b.Metadata[DependenciesMetadata].([]map[string]interface{})
+	raw, ok := b.Metadata[DependenciesMetadata]
 	if !ok {
 		return Dependencies{}, nil
 	}
 
+	deps, ok := raw.([]map[string]interface{})
+	if !ok {
+		return Dependencies{}, fmt.Errorf("%s must be an array of tables", DependenciesMetadata)
+	}
+
 	var dependencies Dependencies
 	for _, dep := range deps {
 		d, err := NewDependency(dep)
@@ -82,6 +93,117 @@ func (b Buildpack) DefaultVersion(id string) (string, error) {
 	return version, nil
 }
 
+// ResolveVersion resolves a version constraint for a dependency id.  An empty or "default" version resolves to the
+// default version from ResolveDefaultVersion and a version that matches a key in the version-aliases metadata for the
+// id resolves to the mapped value.  Resolution repeats, so an alias may map to "default" or to another alias.  The
+// source of the version is logged, whether it is explicit or a default.
+func (b Buildpack) ResolveVersion(id string, version string) (string, error) {
+	if version != "" && version != "default" {
+		b.logger.Body("Using %s version %s from explicit request", id, version)
+	}
+
+	return b.resolveVersion(id, version)
+}
+
+func (b Buildpack) resolveVersion(id string, version string) (string, error) {
+	seen := make(map[string]bool)
+
+	for !seen[version] {
+		seen[version] = true
+
+		var resolved string
+		if version == "" || version == "default" {
+			v, source, err := b.ResolveDefaultVersion(id)
+			if err != nil {
+				return "", err
+			}
+
+			if v == "" {
+				return "", nil
+			}
+
+			b.logger.Body("Using %s default version %s from %s", id, v, source)
+			resolved = v
+		} else {
+			v, ok, err := b.versionAlias(id, version)
+			if err != nil {
+				return "", err
+			}
+
+			if !ok {
+				return version, nil
+			}
+			resolved = v
+		}
+
+		b.logger.Debug("Resolved %s version %s to %s", id, version, resolved)
+		version = resolved
+	}
+
+	return "", fmt.Errorf("%s version %s has a circular alias", id, version)
+}
+
+func (b Buildpack) versionAlias(id string, version string) (string, bool, error) {
+	aliases, ok := b.Metadata[VersionAliases].(map[string]interface{})
+	if !ok {
+		return "", false, nil
+	}
+
+	a, ok := aliases[id]
+	if !ok {
+		return "", false, nil
+	}
+
+	m, ok := a.(map[string]interface{})
+	if !ok {
+		return "", false, fmt.Errorf("%s does not map to a table in %s map", id, VersionAliases)
+	}
+
+	v, ok := m[version]
+	if !ok {
+		return "", false, nil
+	}
+
+	alias, ok := v.(string)
+	if !ok {
+		return "", false, fmt.Errorf("%s alias %s does not map to a string in %s map", id, version, VersionAliases)
+	}
+
+	return alias, true, nil
+}
+
+// StackLineage returns a stack followed by its ancestors in the stack-parents metadata.  The stack-parents metadata
+// maps a stack id to the id of a stack whose dependencies are compatible with it, such as the stack it is an alias for
+// or is derived from.
+func (b Buildpack) StackLineage(s stack.Stack) ([]stack.Stack, error) {
+	lineage := []stack.Stack{s}
+
+	parents, ok := b.Metadata[StackParents].(map[string]interface{})
+	if !ok {
+		return lineage, nil
+	}
+
+	for {
+		p, ok := parents[string(lineage[len(lineage)-1])]
+		if !ok {
+			return lineage, nil
+		}
+
+		parent, ok := p.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s does not map to a string in %s map", lineage[len(lineage)-1], StackParents)
+		}
+
+		for _, l := range lineage {
+			if l == stack.Stack(parent) {
+				return nil, fmt.Errorf("stack %s has a circular parent", s)
+			}
+		}
+
+		lineage = append(lineage, stack.Stack(parent))
+	}
+}
+
 // Identity make Buildpack satisfy the Identifiable interface.
 func (b Buildpack) Identity() (string, string) {
 	return b.Info.Name, b.Info.Version
@@ -113,14 +235,13 @@ func (b Buildpack) PrePackage() (string, bool) {
 	return p, ok
 }
 
+// RuntimeDependency returns the best dependency for an id, version, and stack, using ResolveVersion to interpret the
+// version, accepting dependencies compatible with any stack in the StackLineage, and selecting for the
+// CurrentPlatform.  The dependency is evaluated against the buildpack's LicensePolicy.
 func (b Buildpack) RuntimeDependency(id, version string, stack stack.Stack) (Dependency, error) {
-	var err error
-
-	if version == "" || version == "default" {
-		version, err = b.DefaultVersion(id)
-		if err != nil {
-			return Dependency{}, err
-		}
+	version, err := b.ResolveVersion(id, version)
+	if err != nil {
+		return Dependency{}, err
 	}
 
 	deps, err := b.Dependencies()
@@ -128,5 +249,24 @@ func (b Buildpack) RuntimeDependency(id, version string, stack stack.Stack) (Dep
 		return Dependency{}, err
 	}
 
-	return deps.Best(id, version, stack)
+	stacks, err := b.StackLineage(stack)
+	if err != nil {
+		return Dependency{}, err
+	}
+
+	dep, err := deps.best(id, version, CurrentPlatform(), stacks...)
+	if err != nil {
+		return Dependency{}, err
+	}
+
+	policy, err := b.LicensePolicy()
+	if err != nil {
+		return Dependency{}, err
+	}
+
+	if err := policy.Evaluate(dep, b.logger); err != nil {
+		return Dependency{}, err
+	}
+
+	return dep, nil
 }
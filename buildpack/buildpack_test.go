@@ -204,6 +204,128 @@ func TestBuildpack(t *testing.T) {
 			})
 		})
 
+		when("ResolveVersion", func() {
+			b := buildpack.Buildpack{Buildpack: bp.Buildpack{
+				Metadata: bp.Metadata{
+					buildpack.DefaultVersions: map[string]interface{}{
+						"test-id": "1.2.*",
+					},
+					buildpack.VersionAliases: map[string]interface{}{
+						"test-id": map[string]interface{}{
+							"lts/*":      "1.*",
+							"stable":     "lts/*",
+							"current":    "default",
+							"circular":   "circular-2",
+							"circular-2": "circular",
+						},
+					},
+				},
+			}}
+
+			it("resolves default version", func() {
+				g.Expect(b.ResolveVersion("test-id", "")).To(gomega.Equal("1.2.*"))
+				g.Expect(b.ResolveVersion("test-id", "default")).To(gomega.Equal("1.2.*"))
+			})
+
+			it("resolves aliases", func() {
+				g.Expect(b.ResolveVersion("test-id", "lts/*")).To(gomega.Equal("1.*"))
+				g.Expect(b.ResolveVersion("test-id", "stable")).To(gomega.Equal("1.*"))
+				g.Expect(b.ResolveVersion("test-id", "current")).To(gomega.Equal("1.2.*"))
+			})
+
+			it("does not change other versions", func() {
+				g.Expect(b.ResolveVersion("test-id", "2.*")).To(gomega.Equal("2.*"))
+				g.Expect(b.ResolveVersion("test-other-id", "lts/*")).To(gomega.Equal("lts/*"))
+			})
+
+			it("returns error for circular aliases", func() {
+				_, err := b.ResolveVersion("test-id", "circular")
+				g.Expect(err).To(gomega.MatchError("test-id version circular has a circular alias"))
+			})
+		})
+
+		when("StackLineage", func() {
+			it("returns stack without parents", func() {
+				g.Expect(buildpack.Buildpack{}.StackLineage("test-stack")).To(gomega.Equal([]stack.Stack{"test-stack"}))
+			})
+
+			it("returns stack and its ancestors", func() {
+				b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+					buildpack.StackParents: map[string]interface{}{
+						"test-stack-tiny": "test-stack-base",
+						"test-stack-base": "test-stack",
+					},
+				}}}
+
+				g.Expect(b.StackLineage("test-stack-tiny")).
+					To(gomega.Equal([]stack.Stack{"test-stack-tiny", "test-stack-base", "test-stack"}))
+			})
+
+			it("returns error for circular parents", func() {
+				b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+					buildpack.StackParents: map[string]interface{}{
+						"test-stack-1": "test-stack-2",
+						"test-stack-2": "test-stack-1",
+					},
+				}}}
+
+				_, err := b.StackLineage("test-stack-1")
+				g.Expect(err).To(gomega.MatchError("stack test-stack-1 has a circular parent"))
+			})
+
+			it("selects runtime dependencies of parent stacks", func() {
+				b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+					buildpack.DependenciesMetadata: []map[string]interface{}{TestDep1},
+					buildpack.StackParents: map[string]interface{}{
+						"test-stack-tiny": "test-stack-1a",
+					},
+				}}}
+
+				dep, err := b.RuntimeDependency("test-id-1", "1.0", "test-stack-tiny")
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(dep.ID).To(gomega.Equal("test-id-1"))
+			})
+			it("prefers exact stack, then parent stack, then wildcard stack", func() {
+				dep := func(uri string, stack string) map[string]interface{} {
+					return map[string]interface{}{
+						"id":      "test-id",
+						"name":    "test-name",
+						"version": "1.0",
+						"uri":     uri,
+						"sha256":  "test-sha256",
+						"stacks":  []interface{}{stack},
+					}
+				}
+
+				b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+					buildpack.DependenciesMetadata: []map[string]interface{}{
+						dep("test-uri-exact", "test-stack-tiny"),
+						dep("test-uri-parent", "test-stack-parent"),
+						dep("test-uri-wildcard", "*"),
+					},
+					buildpack.StackParents: map[string]interface{}{
+						"test-stack-tiny": "test-stack-parent",
+					},
+				}}}
+
+				d, err := b.RuntimeDependency("test-id", "1.0", "test-stack-tiny")
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(d.URI).To(gomega.Equal("test-uri-exact"))
+
+				d, err = b.RuntimeDependency("test-id", "1.0", "test-stack-other")
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(d.URI).To(gomega.Equal("test-uri-wildcard"))
+
+				b.Metadata[buildpack.DependenciesMetadata] = []map[string]interface{}{
+					dep("test-uri-parent", "test-stack-parent"),
+					dep("test-uri-wildcard", "*"),
+				}
+				d, err = b.RuntimeDependency("test-id", "1.0", "test-stack-tiny")
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(d.URI).To(gomega.Equal("test-uri-parent"))
+			})
+		})
+
 		when("RuntimeDependency", func(){
 			var (
 				expectedDep buildpack.Dependency
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+)
+
+// ConfigurationsMetadata is the buildpack metadata section that declares the environment variables that configure the
+// buildpack.
+const ConfigurationsMetadata = "configurations"
+
+// Configuration declares an environment variable that configures the buildpack.
+type Configuration struct {
+	// Name is the name of the environment variable.
+	Name string `mapstruct:"name" toml:"name"`
+
+	// Default is the value used when the environment variable is not set.
+	Default string `mapstruct:"default" toml:"default"`
+
+	// Description describes the effect of the environment variable.
+	Description string `mapstruct:"description" toml:"description"`
+
+	// Build indicates that the environment variable is read at build time.
+	Build bool `mapstruct:"build" toml:"build"`
+
+	// Launch indicates that the environment variable is read at launch time.
+	Launch bool `mapstruct:"launch" toml:"launch"`
+}
+
+// Configurations returns the configurations declared in the buildpack metadata.
+func (b Buildpack) Configurations() ([]Configuration, error) {
+	var configurations []Configuration
+
+	if err := b.DecodeMetadata(ConfigurationsMetadata, &configurations); err != nil {
+		return nil, err
+	}
+
+	for i, c := range configurations {
+		if c.Name == "" {
+			return nil, fmt.Errorf("invalid metadata %s: [%d] name is required", ConfigurationsMetadata, i)
+		}
+	}
+
+	return configurations, nil
+}
+
+// ConfigurationResolver resolves the values of declared configurations from the environment.
+type ConfigurationResolver struct {
+	// Configurations are the declared configurations.
+	Configurations []Configuration
+}
+
+// ConfigurationResolver returns a ConfigurationResolver for the configurations declared in the buildpack metadata.
+func (b Buildpack) ConfigurationResolver() (ConfigurationResolver, error) {
+	c, err := b.Configurations()
+	if err != nil {
+		return ConfigurationResolver{}, err
+	}
+
+	return ConfigurationResolver{Configurations: c}, nil
+}
+
+// Resolve returns the value of a configuration and whether it was set in the environment.  If it is not set, the
+// declared default is returned.  Names that are not declared resolve only from the environment.
+func (c ConfigurationResolver) Resolve(name string) (string, bool) {
+	if v, ok := os.LookupEnv(name); ok {
+		return v, true
+	}
+
+	for _, d := range c.Configurations {
+		if d.Name == name {
+			return d.Default, false
+		}
+	}
+
+	return "", false
+}
+
+// ResolveBool returns the value of a configuration as a boolean.  An empty value is false.
+func (c ConfigurationResolver) ResolveBool(name string) (bool, error) {
+	v, _ := c.Resolve(name)
+	if v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid value %q for $%s: %s", v, name, err.Error())
+	}
+
+	return b, nil
+}
+
+// Log writes the effective value of each declared configuration, separated into build and launch configuration.
+func (c ConfigurationResolver) Log(logger logger.Logger) {
+	c.log(logger, "Build configuration:", func(d Configuration) bool { return d.Build })
+	c.log(logger, "Launch configuration:", func(d Configuration) bool { return d.Launch })
+}
+
+func (c ConfigurationResolver) log(logger logger.Logger, header string, include func(Configuration) bool) {
+	var configurations []Configuration
+	for _, d := range c.Configurations {
+		if include(d) {
+			configurations = append(configurations, d)
+		}
+	}
+
+	if len(configurations) == 0 {
+		return
+	}
+
+	logger.Header(header)
+	for _, d := range configurations {
+		v, _ := c.Resolve(d.Name)
+		logger.LaunchConfiguration(fmt.Sprintf("$%s=%s: %s", d.Name, v, d.Description), d.Default)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack_test
+
+import (
+	"bytes"
+	"testing"
+
+	bp "github.com/buildpacks/libbuildpack/v2/buildpack"
+	loggerBp "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestConfigurations(t *testing.T) {
+	spec.Run(t, "Configurations", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+			buildpack.ConfigurationsMetadata: []map[string]interface{}{
+				{
+					"name":        "BP_TEST_BUILD",
+					"default":     "test-default",
+					"description": "test build description",
+					"build":       true,
+				},
+				{
+					"name":        "BP_TEST_LAUNCH",
+					"default":     "false",
+					"description": "test launch description",
+					"launch":      true,
+				},
+			},
+		}}}
+
+		it("returns declared configurations", func() {
+			g.Expect(b.Configurations()).To(gomega.Equal([]buildpack.Configuration{
+				{Name: "BP_TEST_BUILD", Default: "test-default", Description: "test build description", Build: true},
+				{Name: "BP_TEST_LAUNCH", Default: "false", Description: "test launch description", Launch: true},
+			}))
+		})
+
+		it("returns no configurations without metadata", func() {
+			g.Expect(buildpack.Buildpack{}.Configurations()).To(gomega.BeEmpty())
+		})
+
+		it("requires a name", func() {
+			b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+				buildpack.ConfigurationsMetadata: []map[string]interface{}{{"default": "test-default"}},
+			}}}
+
+			_, err := b.Configurations()
+			g.Expect(err).To(gomega.MatchError("invalid metadata configurations: [0] name is required"))
+		})
+
+		it("resolves default value", func() {
+			r, err := b.ConfigurationResolver()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			v, ok := r.Resolve("BP_TEST_BUILD")
+			g.Expect(v).To(gomega.Equal("test-default"))
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("resolves environment value", func() {
+			defer test.ReplaceEnv(t, "BP_TEST_BUILD", "test-value")()
+
+			r, err := b.ConfigurationResolver()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			v, ok := r.Resolve("BP_TEST_BUILD")
+			g.Expect(v).To(gomega.Equal("test-value"))
+			g.Expect(ok).To(gomega.BeTrue())
+		})
+
+		it("resolves boolean value", func() {
+			defer test.ReplaceEnv(t, "BP_TEST_LAUNCH", "true")()
+
+			r, err := b.ConfigurationResolver()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(r.ResolveBool("BP_TEST_LAUNCH")).To(gomega.BeTrue())
+		})
+
+		it("rejects invalid boolean value", func() {
+			defer test.ReplaceEnv(t, "BP_TEST_LAUNCH", "test-value")()
+
+			r, err := b.ConfigurationResolver()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			_, err = r.ResolveBool("BP_TEST_LAUNCH")
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("logs effective configuration", func() {
+			defer test.ReplaceEnv(t, "BP_TEST_BUILD", "test-value")()
+
+			var info bytes.Buffer
+			l := logger.Logger{Logger: loggerBp.NewLogger(nil, &info)}
+
+			r, err := b.ConfigurationResolver()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			r.Log(l)
+
+			g.Expect(info.String()).To(gomega.ContainSubstring("Build configuration:"))
+			g.Expect(info.String()).To(gomega.ContainSubstring("$BP_TEST_BUILD=test-value: test build description. Default"))
+			g.Expect(info.String()).To(gomega.ContainSubstring("Launch configuration:"))
+			g.Expect(info.String()).To(gomega.ContainSubstring("$BP_TEST_LAUNCH=false: test launch description. Default"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+)
+
+// PlatformDefaultVersions is the environment variable containing the path to a TOML file of default versions, keyed by
+// dependency id, that a platform uses to pin default versions across buildpacks.
+const PlatformDefaultVersions = "BP_DEFAULT_VERSIONS_FILE"
+
+var environmentVariableInvalid = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// VersionEnvironmentVariable returns the name of the environment variable that overrides the default version of a
+// dependency.  For example, the variable for openjdk-jdk is BP_OPENJDK_JDK_VERSION.
+func VersionEnvironmentVariable(id string) string {
+	return fmt.Sprintf("BP_%s_VERSION", environmentVariableInvalid.ReplaceAllString(strings.ToUpper(id), "_"))
+}
+
+// ResolveDefaultVersion returns the default version of a dependency and a description of where it came from.  Sources
+// are consulted in order: the VersionEnvironmentVariable for the id, the version for the id in the application's
+// buildpack.yml, the file referenced by PlatformDefaultVersions, and the default-versions metadata of the buildpack.
+// If no source has a default, an empty version is returned.
+func (b Buildpack) ResolveDefaultVersion(id string) (string, string, error) {
+	env := VersionEnvironmentVariable(id)
+	if v, ok := os.LookupEnv(env); ok && v != "" {
+		return v, fmt.Sprintf("$%s", env), nil
+	}
+
+	if v, err := b.buildpackYAMLVersion(id); err != nil {
+		return "", "", err
+	} else if v != "" {
+		return v, b.BuildpackYAML, nil
+	}
+
+	if f, ok := os.LookupEnv(PlatformDefaultVersions); ok && f != "" {
+		if v, err := platformDefaultVersion(f, id); err != nil {
+			return "", "", err
+		} else if v != "" {
+			return v, f, nil
+		}
+	}
+
+	v, err := b.DefaultVersion(id)
+	if err != nil {
+		return "", "", err
+	}
+
+	return v, "buildpack.toml", nil
+}
+
+func (b Buildpack) buildpackYAMLVersion(id string) (string, error) {
+	if b.BuildpackYAML == "" {
+		return "", nil
+	}
+
+	if exists, err := helper.FileExists(b.BuildpackYAML); err != nil {
+		return "", err
+	} else if !exists {
+		return "", nil
+	}
+
+	config := make(map[string]interface{})
+	if err := helper.ReadBuildpackYaml(b.BuildpackYAML, &config); err != nil {
+		return "", err
+	}
+
+	c, ok := config[id].(map[interface{}]interface{})
+	if !ok {
+		return "", nil
+	}
+
+	v, ok := c["version"]
+	if !ok {
+		return "", nil
+	}
+
+	version, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s.version in %s is not a string", id, b.BuildpackYAML)
+	}
+
+	return version, nil
+}
+
+func platformDefaultVersion(file string, id string) (string, error) {
+	defaults := make(map[string]interface{})
+	if _, err := toml.DecodeFile(file, &defaults); err != nil {
+		return "", err
+	}
+
+	v, ok := defaults[id]
+	if !ok {
+		return "", nil
+	}
+
+	version, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s in %s is not a string", id, file)
+	}
+
+	return version, nil
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	bp "github.com/buildpacks/libbuildpack/v2/buildpack"
+	loggerBp "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestDefaultVersions(t *testing.T) {
+	spec.Run(t, "DefaultVersions", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			b        buildpack.Buildpack
+			platform string
+			root     string
+		)
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "default-versions")
+			platform = filepath.Join(root, "default-versions.toml")
+
+			b = buildpack.Buildpack{
+				Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+					buildpack.DefaultVersions: map[string]interface{}{"test-id": "1.*"},
+				}},
+				BuildpackYAML: filepath.Join(root, "buildpack.yml"),
+			}
+		})
+
+		resolve := func() (string, string) {
+			v, s, err := b.ResolveDefaultVersion("test-id")
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			return v, s
+		}
+
+		it("returns environment variable name", func() {
+			g.Expect(buildpack.VersionEnvironmentVariable("openjdk-jdk")).To(gomega.Equal("BP_OPENJDK_JDK_VERSION"))
+			g.Expect(buildpack.VersionEnvironmentVariable("org.test.id")).To(gomega.Equal("BP_ORG_TEST_ID_VERSION"))
+		})
+
+		it("uses buildpack.toml", func() {
+			v, s := resolve()
+			g.Expect(v).To(gomega.Equal("1.*"))
+			g.Expect(s).To(gomega.Equal("buildpack.toml"))
+		})
+
+		it("prefers platform default versions file", func() {
+			test.WriteFile(t, platform, `test-id = "2.*"`)
+			defer test.ReplaceEnv(t, buildpack.PlatformDefaultVersions, platform)()
+
+			v, s := resolve()
+			g.Expect(v).To(gomega.Equal("2.*"))
+			g.Expect(s).To(gomega.Equal(platform))
+		})
+
+		it("prefers buildpack.yml", func() {
+			test.WriteFile(t, platform, `test-id = "2.*"`)
+			defer test.ReplaceEnv(t, buildpack.PlatformDefaultVersions, platform)()
+			test.WriteFile(t, b.BuildpackYAML, "test-id:\n  version: 3.*\n")
+
+			v, s := resolve()
+			g.Expect(v).To(gomega.Equal("3.*"))
+			g.Expect(s).To(gomega.Equal(b.BuildpackYAML))
+		})
+
+		it("prefers environment variable", func() {
+			test.WriteFile(t, platform, `test-id = "2.*"`)
+			defer test.ReplaceEnv(t, buildpack.PlatformDefaultVersions, platform)()
+			test.WriteFile(t, b.BuildpackYAML, "test-id:\n  version: 3.*\n")
+			defer test.ReplaceEnv(t, "BP_TEST_ID_VERSION", "4.*")()
+
+			v, s := resolve()
+			g.Expect(v).To(gomega.Equal("4.*"))
+			g.Expect(s).To(gomega.Equal("$BP_TEST_ID_VERSION"))
+		})
+
+		it("ignores buildpack.yml without version", func() {
+			test.WriteFile(t, b.BuildpackYAML, "test-other-id:\n  version: 3.*\n")
+
+			v, _ := resolve()
+			g.Expect(v).To(gomega.Equal("1.*"))
+		})
+
+		it("logs the source of the version", func() {
+			var info bytes.Buffer
+			yaml := b.BuildpackYAML
+			b = buildpack.NewBuildpack(b.Buildpack, logger.Logger{Logger: loggerBp.NewLogger(nil, &info)})
+			b.BuildpackYAML = yaml
+
+			g.Expect(b.ResolveVersion("test-id", "")).To(gomega.Equal("1.*"))
+			g.Expect(info.String()).To(gomega.ContainSubstring("Using test-id default version 1.* from buildpack.toml"))
+
+			info.Reset()
+			g.Expect(b.ResolveVersion("test-id", "1.0")).To(gomega.Equal("1.0"))
+			g.Expect(info.String()).To(gomega.ContainSubstring("Using test-id version 1.0 from explicit request"))
+		})
+
+		it("resolves runtime dependency version from override", func() {
+			defer test.ReplaceEnv(t, "BP_TEST_ID_VERSION", "2.*")()
+
+			g.Expect(b.ResolveVersion("test-id", "")).To(gomega.Equal("2.*"))
+			g.Expect(b.ResolveVersion("test-id", "1.0")).To(gomega.Equal("1.0"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
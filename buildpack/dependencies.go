@@ -21,7 +21,6 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/Masterminds/semver"
 	"github.com/buildpacks/libbuildpack/v2/stack"
 )
 
@@ -29,9 +28,24 @@ import (
 type Dependencies []Dependency
 
 // Best returns the best (latest version) dependency within a collection of Dependencies.  The candidate set is first
-// filtered by id, version, and stack, then the remaining candidates are sorted for the best result.  If the
-// versionConstraint is not specified (""), then the latest wildcard ("*") is used.
+// filtered by id, version, stack, and the CurrentPlatform, then the remaining candidates are sorted for the best
+// result.  A dependency with the AnyStack wildcard is compatible with every stack.  If the versionConstraint is not
+// specified (""), then the latest wildcard ("*") is used.  Versions that differ only in build metadata, such as
+// 11.0.6+9 and 11.0.6+10, are ordered by that metadata.  Of otherwise equal versions, one for the exact stack is
+// preferred over a wildcard one, and one built for the platform is preferred over a generic one.  Best does not consult
+// the stack-parents metadata; use Buildpack.RuntimeDependency to accept dependencies for the StackLineage of the stack.
+// If no candidate remains, the error describes why each dependency was filtered out.
 func (d Dependencies) Best(id string, versionConstraint string, stack stack.Stack) (Dependency, error) {
+	return d.BestForPlatform(id, versionConstraint, stack, CurrentPlatform())
+}
+
+// BestForPlatform returns the best dependency, like Best, for an explicit platform rather than the CurrentPlatform.
+func (d Dependencies) BestForPlatform(id string, versionConstraint string, stack stack.Stack, platform Platform) (Dependency, error) {
+	return d.best(id, versionConstraint, platform, stack)
+}
+
+// best returns the best dependency that is compatible with the platform and any of the stacks.
+func (d Dependencies) best(id string, versionConstraint string, platform Platform, stacks ...stack.Stack) (Dependency, error) {
 	var candidates Dependencies
 
 	vc := versionConstraint
@@ -39,23 +53,60 @@ func (d Dependencies) Best(id string, versionConstraint string, stack stack.Stac
 		vc = "*"
 	}
 
-	constraint, err := semver.NewConstraint(vc)
+	constraint, err := NewConstraint(vc)
 	if err != nil {
 		return Dependency{}, err
 	}
 
+	var filtered []string
 	for _, c := range d {
-		if c.ID == id && constraint.Check(c.Version.Version) && c.Stacks.contains(stack) {
+		var reasons []string
+
+		if c.ID != id {
+			reasons = append(reasons, "id does not match")
+		}
+
+		if !constraint.Check(c.Version.Version) {
+			reasons = append(reasons, "version does not match constraint")
+		}
+
+		if !c.Stacks.contains(stacks...) {
+			reasons = append(reasons, "stack is not supported")
+		}
+
+		if !platform.supports(c) {
+			reasons = append(reasons, fmt.Sprintf("platform %s is not supported", platform))
+		}
+
+		if len(reasons) == 0 {
 			candidates = append(candidates, c)
+		} else {
+			filtered = append(filtered, fmt.Sprintf("(%s, %s, %s): %s",
+				c.ID, c.Version.Original(), c.Stacks, strings.Join(reasons, ", ")))
 		}
 	}
 
 	if len(candidates) == 0 {
-		return Dependency{}, fmt.Errorf("no valid dependencies for %s, %s, and %s in [%s]", id, vc, stack, d.candidateMessage())
+		return Dependency{}, fmt.Errorf("no valid dependencies for %s, %s, and %s in:\n%s",
+			id, vc, joinStacks(stacks), strings.Join(filtered, "\n"))
 	}
 
 	sort.Slice(candidates, func(i int, j int) bool {
-		return candidates[i].Version.LessThan(candidates[j].Version.Version)
+		if candidates[i].Version.lessThan(candidates[j].Version) {
+			return true
+		}
+
+		if candidates[j].Version.lessThan(candidates[i].Version) {
+			return false
+		}
+
+		// prefer dependencies for the exact stack over those for a parent stack, and those over wildcard ones
+		if ri, rj := candidates[i].Stacks.rank(stacks...), candidates[j].Stacks.rank(stacks...); ri != rj {
+			return ri > rj
+		}
+
+		// prefer dependencies built specifically for the platform over generic ones of the same version
+		return platformSpecificity(candidates[i]) < platformSpecificity(candidates[j])
 	})
 
 	return candidates[len(candidates)-1], nil
@@ -73,11 +124,25 @@ func (d Dependencies) Has(id string) bool {
 	return false
 }
 
-func (d Dependencies) candidateMessage() string {
+func platformSpecificity(dependency Dependency) int {
+	s := 0
+
+	if dependency.OS != "" {
+		s++
+	}
+
+	if dependency.Arch != "" {
+		s++
+	}
+
+	return s
+}
+
+func joinStacks(stacks []stack.Stack) string {
 	var s []string
 
-	for _, c := range d {
-		s = append(s, fmt.Sprintf("(%s, %s, %s)", c.ID, c.Version.Original(), c.Stacks))
+	for _, v := range stacks {
+		s = append(s, string(v))
 	}
 
 	return strings.Join(s, ", ")
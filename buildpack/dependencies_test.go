@@ -118,6 +118,38 @@ func TestDependencies(t *testing.T) {
 			g.Expect(d.Best("test-id", "1.0", "test-stack-3")).To(gomega.Equal(expected))
 		})
 
+		it("matches wildcard stack", func() {
+			d := buildpack.Dependencies{
+				buildpack.Dependency{
+					ID:      "test-id",
+					Name:    "test-name",
+					Version: internal.NewTestVersion(t, "1.0"),
+					URI:     "test-uri",
+					SHA256:  "test-sha256",
+					Stacks:  buildpack.Stacks{buildpack.AnyStack}},
+			}
+
+			g.Expect(d.Best("test-id", "1.0", "test-stack-1")).To(gomega.Equal(d[0]))
+		})
+
+		it("prefers exact stack over wildcard stack of the same version", func() {
+			exact := buildpack.Dependency{
+				ID:      "test-id",
+				Version: internal.NewTestVersion(t, "1.0"),
+				URI:     "test-uri-exact",
+				Stacks:  buildpack.Stacks{"test-stack-1"},
+			}
+			wildcard := buildpack.Dependency{
+				ID:      "test-id",
+				Version: internal.NewTestVersion(t, "1.0"),
+				URI:     "test-uri-wildcard",
+				Stacks:  buildpack.Stacks{buildpack.AnyStack},
+			}
+
+			g.Expect(buildpack.Dependencies{exact, wildcard}.Best("test-id", "1.0", "test-stack-1")).To(gomega.Equal(exact))
+			g.Expect(buildpack.Dependencies{wildcard, exact}.Best("test-id", "1.0", "test-stack-1")).To(gomega.Equal(exact))
+		})
+
 		it("returns the best dependency", func() {
 			d := buildpack.Dependencies{
 				buildpack.Dependency{
@@ -246,7 +278,10 @@ func TestDependencies(t *testing.T) {
 
 			_, err := d.Best("test-id-2", "1.0", "test-stack-1")
 			g.Expect(err).To(gomega.HaveOccurred())
-			expectedError := "no valid dependencies for test-id-2, 1.0, and test-stack-1 in [(test-id, 1.0, [test-stack-1 test-stack-2]), (test-id, 1.0, [test-stack-1 test-stack-3]), (test-id-2, 1.1, [test-stack-1 test-stack-3])]"
+			expectedError := `no valid dependencies for test-id-2, 1.0, and test-stack-1 in:
+(test-id, 1.0, [test-stack-1 test-stack-2]): id does not match
+(test-id, 1.0, [test-stack-1 test-stack-3]): id does not match
+(test-id-2, 1.1, [test-stack-1 test-stack-3]): version does not match constraint`
 			g.Expect(err).To(gomega.MatchError(expectedError))
 		})
 
@@ -18,8 +18,8 @@ package buildpack
 
 import (
 	"fmt"
-
-	"github.com/mitchellh/mapstructure"
+	"strings"
+	"time"
 )
 
 // Dependency represents a buildpack dependency.
@@ -44,18 +44,30 @@ type Dependency struct {
 
 	// Licenses are the stacks the dependency is distributed under.
 	Licenses Licenses `mapstruct:"licenses" toml:"licenses"`
+
+	// OS is the optional operating system the dependency is built for.  If empty, the dependency supports all operating
+	// systems.
+	OS string `mapstruct:"os" toml:"os,omitempty"`
+
+	// Arch is the optional CPU architecture the dependency is built for.  If empty, the dependency supports all
+	// architectures.
+	Arch string `mapstruct:"arch" toml:"arch,omitempty"`
+
+	// DeprecationDate is the optional date after which the buildpack will no longer provide the dependency.
+	DeprecationDate *time.Time `mapstruct:"deprecation_date" toml:"deprecation_date,omitempty"`
+
+	// EOL is the optional date after which the dependency is no longer supported upstream.
+	EOL *time.Time `mapstruct:"eol" toml:"eol,omitempty"`
+
+	// CVEs are the identifiers of the security advisories known to affect the dependency.
+	CVEs []string `mapstruct:"cves" toml:"cves,omitempty"`
 }
 
 // NewDependency makes a Dependency from a generic map describing a Dependency
 func NewDependency(dep map[string]interface{}) (Dependency, error) {
 	var d Dependency
 
-	config := mapstructure.DecoderConfig{
-		DecodeHook: unmarshalText,
-		Result:     &d,
-	}
-
-	decoder, err := mapstructure.NewDecoder(&config)
+	decoder, err := newDecoder(&d)
 	if err != nil {
 		return Dependency{}, err
 	}
@@ -70,40 +82,16 @@ func NewDependency(dep map[string]interface{}) (Dependency, error) {
 // Identity make Buildpack satisfy the Identifiable interface.
 func (d Dependency) Identity() (string, string) {
 	if d.Version.Version != nil {
-		return d.Name, d.Version.Version.Original()
+		return d.Name, d.Version.Original()
 	}
 
 	return d.Name, ""
 }
 
-// Validate ensures that the dependency is valid.
+// Validate ensures that the dependency is valid, reporting every problem in a single error.
 func (d Dependency) Validate() error {
-	if "" == d.ID {
-		return fmt.Errorf("id is required")
-	}
-
-	if "" == d.Name {
-		return fmt.Errorf("name is required")
-	}
-
-	if (Version{} == d.Version) {
-		return fmt.Errorf("version is required")
-	}
-
-	if "" == d.URI {
-		return fmt.Errorf("uri is required")
-	}
-
-	if "" == d.SHA256 {
-		return fmt.Errorf("sha256 is required")
-	}
-
-	if err := d.Stacks.Validate(); err != nil {
-		return err
-	}
-
-	if err := d.Licenses.Validate(); err != nil {
-		return err
+	if problems := d.problems(); len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, ", "))
 	}
 
 	return nil
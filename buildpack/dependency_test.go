@@ -18,6 +18,7 @@ package buildpack_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
 	"github.com/cloudfoundry/libcfbuildpack/v2/internal"
@@ -69,6 +70,30 @@ func TestDependency(t *testing.T) {
 			})
 		})
 
+		it("constructs a dependency with lifecycle metadata", func() {
+			deprecation := time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)
+			eol := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+			g.Expect(buildpack.NewDependency(map[string]interface{}{
+				"id":               "test-id",
+				"version":          "1.0",
+				"deprecation_date": "2020-06-01",
+				"eol":              time.Date(2021, time.January, 1, 12, 30, 0, 0, time.Local),
+				"cves":             []interface{}{"CVE-2020-0001"},
+			})).To(gomega.Equal(buildpack.Dependency{
+				ID:              "test-id",
+				Version:         internal.NewTestVersion(t, "1.0"),
+				DeprecationDate: &deprecation,
+				EOL:             &eol,
+				CVEs:            []string{"CVE-2020-0001"},
+			}))
+		})
+
+		it("returns error for invalid date", func() {
+			_, err := buildpack.NewDependency(map[string]interface{}{"deprecation_date": "June 2020"})
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid date June 2020")))
+		})
+
 		when("Validate", func() {
 			it("validates", func() {
 				g.Expect(buildpack.Dependency{
@@ -84,6 +109,18 @@ func TestDependency(t *testing.T) {
 				}.Validate()).To(gomega.Succeed())
 			})
 
+			it("reports every problem", func() {
+				g.Expect(buildpack.Dependency{
+					Version: internal.NewTestVersion(t, "1.0.0"),
+					URI:     "test-uri",
+					SHA256:  "test-sha256",
+					Stacks:  buildpack.Stacks{"test-stack"},
+					Licenses: buildpack.Licenses{
+						{Type: "test-type"},
+					},
+				}.Validate()).To(gomega.MatchError("id is required, name is required"))
+			})
+
 			it("does not validate with invalid id", func() {
 				g.Expect(buildpack.Dependency{
 					Name:    "test-name",
@@ -0,0 +1,200 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+)
+
+const (
+	// LicensePolicyMetadata is the buildpack metadata section that configures the license policy.  It may contain
+	// "allow" and "deny" arrays of SPDX identifiers and a "mode" of either "fail" or "warn".
+	LicensePolicyMetadata = "license-policy"
+
+	// LicenseAllow is the environment variable a platform uses to configure the SPDX identifiers of allowed licenses.
+	// When set, it replaces the buildpack's allow list.
+	LicenseAllow = "BP_LICENSE_ALLOW"
+
+	// LicenseDeny is the environment variable a platform uses to configure the SPDX identifiers of denied licenses.
+	// When set, it is added to the buildpack's deny list.
+	LicenseDeny = "BP_LICENSE_DENY"
+
+	// LicensePolicyMode is the environment variable a platform uses to configure whether license policy violations
+	// fail the build ("fail") or only log a warning ("warn").
+	LicensePolicyMode = "BP_LICENSE_POLICY"
+)
+
+// LicensePolicy describes which licenses dependencies may be distributed under.  A dependency violates the policy if
+// any of its licenses is denied or, when an allow list is configured, if any of its licenses is not allowed.  SPDX
+// identifiers are compared case-insensitively.
+type LicensePolicy struct {
+	// Allow are the SPDX identifiers of allowed licenses.  If empty, all licenses that are not denied are allowed.
+	Allow []string
+
+	// Deny are the SPDX identifiers of denied licenses.
+	Deny []string
+
+	// Warn indicates that violations should be logged as warnings rather than returned as errors.
+	Warn bool
+}
+
+// LicensePolicy returns the license policy configured in the buildpack metadata and overridden by the platform.
+func (b Buildpack) LicensePolicy() (LicensePolicy, error) {
+	var p LicensePolicy
+
+	if m, ok := b.Metadata[LicensePolicyMetadata].(map[string]interface{}); ok {
+		var err error
+
+		if p.Allow, err = stringArray(m, "allow"); err != nil {
+			return LicensePolicy{}, err
+		}
+
+		if p.Deny, err = stringArray(m, "deny"); err != nil {
+			return LicensePolicy{}, err
+		}
+
+		if mode, ok := m["mode"]; ok {
+			s, ok := mode.(string)
+			if !ok {
+				return LicensePolicy{}, fmt.Errorf("%s.mode is not a string", LicensePolicyMetadata)
+			}
+
+			if p.Warn, err = warnMode(s); err != nil {
+				return LicensePolicy{}, err
+			}
+		}
+	}
+
+	if s, ok := os.LookupEnv(LicenseAllow); ok {
+		p.Allow = splitIdentifiers(s)
+	}
+
+	if s, ok := os.LookupEnv(LicenseDeny); ok {
+		p.Deny = append(p.Deny, splitIdentifiers(s)...)
+	}
+
+	if s, ok := os.LookupEnv(LicensePolicyMode); ok {
+		var err error
+		if p.Warn, err = warnMode(s); err != nil {
+			return LicensePolicy{}, err
+		}
+	}
+
+	return p, nil
+}
+
+// Evaluate checks a dependency against the policy.  If the dependency violates the policy, an error describing every
+// violation is returned or, if the policy only warns, the violations are logged.
+func (p LicensePolicy) Evaluate(dependency Dependency, logger logger.Logger) error {
+	violations := p.Violations(dependency)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if p.Warn {
+		logger.HeaderWarning("%s %s violates the license policy", dependency.ID, dependency.Version.Original())
+		for _, v := range violations {
+			logger.BodyWarning(v)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%s %s violates the license policy:\n%s",
+		dependency.ID, dependency.Version.Original(), strings.Join(violations, "\n"))
+}
+
+// Violations returns a description of each way that a dependency violates the policy.
+func (p LicensePolicy) Violations(dependency Dependency) []string {
+	var violations []string
+
+	if len(p.Allow) > 0 && len(dependency.Licenses) == 0 {
+		violations = append(violations, "no licenses are declared")
+	}
+
+	for _, l := range dependency.Licenses {
+		if l.Type == "" {
+			if len(p.Allow) > 0 {
+				violations = append(violations, fmt.Sprintf("license %s has no SPDX identifier", l.URI))
+			}
+			continue
+		}
+
+		if containsIdentifier(p.Deny, l.Type) {
+			violations = append(violations, fmt.Sprintf("license %s is denied", l.Type))
+		} else if len(p.Allow) > 0 && !containsIdentifier(p.Allow, l.Type) {
+			violations = append(violations, fmt.Sprintf("license %s is not allowed", l.Type))
+		}
+	}
+
+	return violations
+}
+
+func containsIdentifier(identifiers []string, identifier string) bool {
+	for _, i := range identifiers {
+		if strings.EqualFold(i, identifier) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func splitIdentifiers(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+func stringArray(m map[string]interface{}, key string) ([]string, error) {
+	v, ok := m[key]
+	if !ok {
+		return nil, nil
+	}
+
+	candidates, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s.%s is not an array of strings", LicensePolicyMetadata, key)
+	}
+
+	var s []string
+	for _, c := range candidates {
+		i, ok := c.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s.%s is not an array of strings", LicensePolicyMetadata, key)
+		}
+
+		s = append(s, i)
+	}
+
+	return s, nil
+}
+
+func warnMode(mode string) (bool, error) {
+	switch mode {
+	case "fail":
+		return false, nil
+	case "warn":
+		return true, nil
+	default:
+		return false, fmt.Errorf("license policy mode must be one of fail or warn, got %q", mode)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack_test
+
+import (
+	"bytes"
+	"testing"
+
+	bp "github.com/buildpacks/libbuildpack/v2/buildpack"
+	loggerBp "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/internal"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestLicensePolicy(t *testing.T) {
+	spec.Run(t, "LicensePolicy", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		dependency := func(licenses ...buildpack.License) buildpack.Dependency {
+			return buildpack.Dependency{ID: "test-id", Version: internal.NewTestVersion(t, "1.0"), Licenses: licenses}
+		}
+
+		when("configuration", func() {
+
+			it("returns empty policy without configuration", func() {
+				g.Expect(buildpack.Buildpack{}.LicensePolicy()).To(gomega.Equal(buildpack.LicensePolicy{}))
+			})
+
+			it("returns policy from buildpack metadata", func() {
+				b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+					buildpack.LicensePolicyMetadata: map[string]interface{}{
+						"allow": []interface{}{"Apache-2.0", "MIT"},
+						"deny":  []interface{}{"GPL-3.0-only"},
+						"mode":  "warn",
+					},
+				}}}
+
+				g.Expect(b.LicensePolicy()).To(gomega.Equal(buildpack.LicensePolicy{
+					Allow: []string{"Apache-2.0", "MIT"},
+					Deny:  []string{"GPL-3.0-only"},
+					Warn:  true,
+				}))
+			})
+
+			it("overrides policy from platform", func() {
+				defer test.ReplaceEnv(t, buildpack.LicenseAllow, "BSD-3-Clause, MIT")()
+				defer test.ReplaceEnv(t, buildpack.LicenseDeny, "AGPL-3.0-only")()
+				defer test.ReplaceEnv(t, buildpack.LicensePolicyMode, "fail")()
+
+				b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+					buildpack.LicensePolicyMetadata: map[string]interface{}{
+						"allow": []interface{}{"Apache-2.0"},
+						"deny":  []interface{}{"GPL-3.0-only"},
+						"mode":  "warn",
+					},
+				}}}
+
+				g.Expect(b.LicensePolicy()).To(gomega.Equal(buildpack.LicensePolicy{
+					Allow: []string{"BSD-3-Clause", "MIT"},
+					Deny:  []string{"GPL-3.0-only", "AGPL-3.0-only"},
+				}))
+			})
+
+			it("returns error for invalid mode", func() {
+				defer test.ReplaceEnv(t, buildpack.LicensePolicyMode, "ignore")()
+
+				_, err := buildpack.Buildpack{}.LicensePolicy()
+				g.Expect(err).To(gomega.MatchError(`license policy mode must be one of fail or warn, got "ignore"`))
+			})
+		})
+
+		when("violations", func() {
+
+			it("allows everything without lists", func() {
+				g.Expect(buildpack.LicensePolicy{}.Violations(dependency())).To(gomega.BeEmpty())
+			})
+
+			it("reports denied licenses", func() {
+				p := buildpack.LicensePolicy{Deny: []string{"gpl-3.0-only"}}
+
+				g.Expect(p.Violations(dependency(buildpack.License{Type: "GPL-3.0-only"}, buildpack.License{Type: "MIT"}))).
+					To(gomega.Equal([]string{"license GPL-3.0-only is denied"}))
+			})
+
+			it("reports licenses that are not allowed", func() {
+				p := buildpack.LicensePolicy{Allow: []string{"MIT"}}
+
+				g.Expect(p.Violations(dependency(buildpack.License{Type: "MIT"}, buildpack.License{Type: "EPL-2.0"},
+					buildpack.License{URI: "https://test.com/license"}))).
+					To(gomega.Equal([]string{
+						"license EPL-2.0 is not allowed",
+						"license https://test.com/license has no SPDX identifier",
+					}))
+				g.Expect(p.Violations(dependency())).To(gomega.Equal([]string{"no licenses are declared"}))
+			})
+		})
+
+		when("evaluation", func() {
+
+			it("returns error for violations", func() {
+				p := buildpack.LicensePolicy{Deny: []string{"GPL-3.0-only"}}
+
+				g.Expect(p.Evaluate(dependency(buildpack.License{Type: "GPL-3.0-only"}), logger.Logger{})).
+					To(gomega.MatchError("test-id 1.0 violates the license policy:\nlicense GPL-3.0-only is denied"))
+			})
+
+			it("logs warning for violations", func() {
+				var info bytes.Buffer
+				p := buildpack.LicensePolicy{Deny: []string{"GPL-3.0-only"}, Warn: true}
+
+				g.Expect(p.Evaluate(dependency(buildpack.License{Type: "GPL-3.0-only"}),
+					logger.Logger{Logger: loggerBp.NewLogger(nil, &info)})).To(gomega.Succeed())
+				g.Expect(info.String()).To(gomega.ContainSubstring("test-id 1.0 violates the license policy"))
+				g.Expect(info.String()).To(gomega.ContainSubstring("license GPL-3.0-only is denied"))
+			})
+
+			it("fails RuntimeDependency for violations", func() {
+				b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+					buildpack.DependenciesMetadata: []map[string]interface{}{TestDep1},
+					buildpack.LicensePolicyMetadata: map[string]interface{}{
+						"deny": []interface{}{"test-type-2"},
+					},
+				}}}
+
+				_, err := b.RuntimeDependency("test-id-1", "1.0", "test-stack-1a")
+				g.Expect(err).To(gomega.MatchError("test-id-1 1.0 violates the license policy:\nlicense test-type-2 is denied"))
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+)
+
+// LifecycleWarningPeriod is how long before a dependency's deprecation date or end-of-life that warnings start.
+const LifecycleWarningPeriod = 30 * 24 * time.Hour
+
+// LifecycleWarnings returns a description of each lifecycle concern about the dependency at a given time: a
+// deprecation date or end-of-life that has passed or is within the LifecycleWarningPeriod, and any known CVEs.
+func (d Dependency) LifecycleWarnings(now time.Time) []string {
+	var warnings []string
+
+	if w := lifecycleWarning(d.DeprecationDate, now, "deprecated since %s", "deprecated on %s"); w != "" {
+		warnings = append(warnings, w)
+	}
+
+	if w := lifecycleWarning(d.EOL, now, "end-of-life since %s", "end-of-life on %s"); w != "" {
+		warnings = append(warnings, w)
+	}
+
+	if len(d.CVEs) > 0 {
+		warnings = append(warnings, fmt.Sprintf("affected by %s", strings.Join(d.CVEs, ", ")))
+	}
+
+	return warnings
+}
+
+// LogLifecycleWarnings logs the current LifecycleWarnings of the dependency, if there are any.
+func (d Dependency) LogLifecycleWarnings(logger logger.Logger) {
+	warnings := d.LifecycleWarnings(time.Now())
+	if len(warnings) == 0 {
+		return
+	}
+
+	logger.HeaderWarning("%s %s has lifecycle warnings", d.ID, d.Version.Original())
+	for _, w := range warnings {
+		logger.BodyWarning(w)
+	}
+}
+
+func lifecycleWarning(date *time.Time, now time.Time, past string, upcoming string) string {
+	if date == nil {
+		return ""
+	}
+
+	if !now.Before(*date) {
+		return fmt.Sprintf(past, date.Format("2006-01-02"))
+	}
+
+	if date.Sub(now) <= LifecycleWarningPeriod {
+		return fmt.Sprintf(upcoming, date.Format("2006-01-02"))
+	}
+
+	return ""
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestLifecycle(t *testing.T) {
+	spec.Run(t, "Lifecycle", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		now := time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+		date := func(year int, month time.Month, day int) *time.Time {
+			d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+			return &d
+		}
+
+		it("has no warnings without lifecycle metadata", func() {
+			g.Expect(buildpack.Dependency{}.LifecycleWarnings(now)).To(gomega.BeEmpty())
+		})
+
+		it("has no warnings for distant dates", func() {
+			d := buildpack.Dependency{DeprecationDate: date(2021, time.January, 1), EOL: date(2021, time.January, 1)}
+
+			g.Expect(d.LifecycleWarnings(now)).To(gomega.BeEmpty())
+		})
+
+		it("warns about upcoming dates", func() {
+			d := buildpack.Dependency{DeprecationDate: date(2020, time.June, 15), EOL: date(2020, time.July, 1)}
+
+			g.Expect(d.LifecycleWarnings(now)).To(gomega.Equal([]string{
+				"deprecated on 2020-06-15",
+				"end-of-life on 2020-07-01",
+			}))
+		})
+
+		it("warns about past dates", func() {
+			d := buildpack.Dependency{DeprecationDate: date(2020, time.June, 1), EOL: date(2020, time.January, 1)}
+
+			g.Expect(d.LifecycleWarnings(now)).To(gomega.Equal([]string{
+				"deprecated since 2020-06-01",
+				"end-of-life since 2020-01-01",
+			}))
+		})
+
+		it("warns about CVEs", func() {
+			d := buildpack.Dependency{CVEs: []string{"CVE-2020-0001", "CVE-2020-0002"}}
+
+			g.Expect(d.LifecycleWarnings(now)).To(gomega.Equal([]string{"affected by CVE-2020-0001, CVE-2020-0002"}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// DecodeMetadata decodes a section of the buildpack metadata into a typed value.  The key is a '.' separated path
+// into the metadata, such as "configurations" or "my-section.sub-section", and an empty key decodes all of the
+// metadata.  Fields are matched using the "mapstruct" tag, falling back to a case-insensitive match of the field name,
+// and Version fields are parsed as they are for dependencies.  A missing section leaves the value unchanged and
+// decoding errors name each offending key.
+func (b Buildpack) DecodeMetadata(key string, value interface{}) error {
+	var section interface{} = map[string]interface{}(b.Metadata)
+
+	if key != "" {
+		for _, k := range strings.Split(key, ".") {
+			m, ok := section.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("invalid metadata %s: %s is not a table", key, k)
+			}
+
+			if section, ok = m[k]; !ok {
+				return nil
+			}
+		}
+	}
+
+	decoder, err := newDecoder(value)
+	if err != nil {
+		return err
+	}
+
+	if err := decoder.Decode(section); err != nil {
+		if e, ok := err.(*mapstructure.Error); ok {
+			return fmt.Errorf("invalid metadata %s:\n%s", key, strings.Join(e.Errors, "\n"))
+		}
+
+		return fmt.Errorf("invalid metadata %s: %s", key, err.Error())
+	}
+
+	return nil
+}
+
+func newDecoder(result interface{}) (*mapstructure.Decoder, error) {
+	return mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: unmarshalText,
+		Result:     result,
+		TagName:    "mapstruct",
+	})
+}
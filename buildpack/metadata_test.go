@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack_test
+
+import (
+	"testing"
+
+	bp "github.com/buildpacks/libbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/internal"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestMetadata(t *testing.T) {
+	spec.Run(t, "Metadata", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		type agent struct {
+			Name     string            `mapstruct:"name"`
+			Minimum  buildpack.Version `mapstruct:"minimum-version"`
+			Enabled  bool              `mapstruct:"enabled"`
+			Includes []string          `mapstruct:"includes"`
+		}
+
+		b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+			"agents": map[string]interface{}{
+				"test-agent": map[string]interface{}{
+					"name":            "test-name",
+					"minimum-version": "1.2.3",
+					"enabled":         true,
+					"includes":        []interface{}{"test-include"},
+				},
+			},
+			"invalid-agent": map[string]interface{}{
+				"minimum-version": "test-version",
+				"enabled":         "test-enabled",
+			},
+		}}}
+
+		it("decodes nested section", func() {
+			var a agent
+			g.Expect(b.DecodeMetadata("agents.test-agent", &a)).To(gomega.Succeed())
+
+			g.Expect(a).To(gomega.Equal(agent{
+				Name:     "test-name",
+				Minimum:  internal.NewTestVersion(t, "1.2.3"),
+				Enabled:  true,
+				Includes: []string{"test-include"},
+			}))
+		})
+
+		it("decodes map of sections", func() {
+			var a map[string]agent
+			g.Expect(b.DecodeMetadata("agents", &a)).To(gomega.Succeed())
+
+			g.Expect(a).To(gomega.HaveKey("test-agent"))
+		})
+
+		it("leaves value unchanged for missing section", func() {
+			a := agent{Name: "test-default"}
+			g.Expect(b.DecodeMetadata("agents.test-other-agent", &a)).To(gomega.Succeed())
+
+			g.Expect(a).To(gomega.Equal(agent{Name: "test-default"}))
+		})
+
+		it("returns error naming offending keys", func() {
+			var a agent
+			err := b.DecodeMetadata("invalid-agent", &a)
+
+			g.Expect(err).To(gomega.MatchError(gomega.HavePrefix("invalid metadata invalid-agent:\n")))
+			g.Expect(err.Error()).To(gomega.ContainSubstring("'minimum-version': invalid semantic version test-version"))
+			g.Expect(err.Error()).To(gomega.ContainSubstring("'enabled' expected type 'bool'"))
+		})
+
+		it("returns error for path through non-table", func() {
+			var a agent
+			g.Expect(b.DecodeMetadata("agents.test-agent.name.value", &a)).
+				To(gomega.MatchError("invalid metadata agents.test-agent.name.value: value is not a table"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack
+
+import (
+	"os"
+	"runtime"
+)
+
+const (
+	// TargetArch is the environment variable that overrides the CPU architecture dependencies are selected for.
+	TargetArch = "BP_ARCH"
+
+	// TargetOS is the environment variable that overrides the operating system dependencies are selected for.
+	TargetOS = "BP_OS"
+)
+
+// Platform is the operating system and CPU architecture that dependencies are selected for.
+type Platform struct {
+	// OS is the operating system, using GOOS values such as linux.
+	OS string
+
+	// Arch is the CPU architecture, using GOARCH values such as amd64 or arm64.
+	Arch string
+}
+
+// CurrentPlatform returns the platform of the running process, overridden by the TargetOS and TargetArch environment
+// variables if they are set.
+func CurrentPlatform() Platform {
+	p := Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+
+	if s, ok := os.LookupEnv(TargetOS); ok {
+		p.OS = s
+	}
+
+	if s, ok := os.LookupEnv(TargetArch); ok {
+		p.Arch = s
+	}
+
+	return p
+}
+
+// String makes Platform satisfy the Stringer interface.
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
+// supports indicates whether a dependency can be used on the platform.  Dependencies that do not declare an os or arch
+// are compatible with all operating systems or architectures.
+func (p Platform) supports(dependency Dependency) bool {
+	return (dependency.OS == "" || dependency.OS == p.OS) && (dependency.Arch == "" || dependency.Arch == p.Arch)
+}
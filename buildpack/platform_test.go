@@ -0,0 +1,94 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/internal"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestPlatform(t *testing.T) {
+	spec.Run(t, "Platform", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		dependency := func(version string, os string, arch string) buildpack.Dependency {
+			return buildpack.Dependency{
+				ID:      "test-id",
+				Version: internal.NewTestVersion(t, version),
+				Stacks:  buildpack.Stacks{"test-stack"},
+				OS:      os,
+				Arch:    arch,
+			}
+		}
+
+		it("returns the running platform", func() {
+			g.Expect(buildpack.CurrentPlatform()).To(gomega.Equal(buildpack.Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}))
+		})
+
+		it("overrides the running platform", func() {
+			defer test.ReplaceEnv(t, buildpack.TargetOS, "test-os")()
+			defer test.ReplaceEnv(t, buildpack.TargetArch, "test-arch")()
+
+			g.Expect(buildpack.CurrentPlatform()).To(gomega.Equal(buildpack.Platform{OS: "test-os", Arch: "test-arch"}))
+		})
+
+		it("filters by platform", func() {
+			d := buildpack.Dependencies{
+				dependency("2.0", "linux", "arm64"),
+				dependency("1.0", "linux", "amd64"),
+			}
+
+			g.Expect(d.BestForPlatform("test-id", "", "test-stack", buildpack.Platform{OS: "linux", Arch: "amd64"})).
+				To(gomega.Equal(d[1]))
+
+			_, err := d.BestForPlatform("test-id", "", "test-stack", buildpack.Platform{OS: "windows", Arch: "amd64"})
+			g.Expect(err).To(gomega.MatchError(`no valid dependencies for test-id, *, and test-stack in:
+(test-id, 2.0, [test-stack]): platform windows/amd64 is not supported
+(test-id, 1.0, [test-stack]): platform windows/amd64 is not supported`))
+		})
+
+		it("prefers platform specific dependencies", func() {
+			d := buildpack.Dependencies{
+				dependency("1.0", "", ""),
+				dependency("1.0", "linux", "arm64"),
+				dependency("1.0", "", ""),
+			}
+
+			g.Expect(d.BestForPlatform("test-id", "", "test-stack", buildpack.Platform{OS: "linux", Arch: "arm64"})).
+				To(gomega.Equal(d[1]))
+		})
+
+		it("selects by overridden platform", func() {
+			defer test.ReplaceEnv(t, buildpack.TargetArch, "arm64")()
+
+			d := buildpack.Dependencies{
+				dependency("1.0", "", "amd64"),
+				dependency("1.0", "", "arm64"),
+			}
+
+			g.Expect(d.Best("test-id", "", "test-stack")).To(gomega.Equal(d[1]))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -22,24 +22,57 @@ import (
 	"github.com/buildpacks/libbuildpack/v2/stack"
 )
 
+// AnyStack is the stack id that indicates compatibility with every stack.
+const AnyStack stack.Stack = "*"
+
 // Stacks is a collection of stack ids.
 type Stacks []stack.Stack
 
-// Validate ensures that there is at least one stack.
+// Validate ensures that there is at least one stack and that the AnyStack wildcard is not combined with other stacks.
 func (s Stacks) Validate() error {
 	if len(s) == 0 {
 		return fmt.Errorf("at least one stack is required")
 	}
 
+	if len(s) > 1 {
+		for _, v := range s {
+			if v == AnyStack {
+				return fmt.Errorf("stack %s must not be combined with other stacks", AnyStack)
+			}
+		}
+	}
+
 	return nil
 }
 
-func (s Stacks) contains(stack stack.Stack) bool {
+// contains indicates whether the collection is compatible with any of the candidate stacks.
+func (s Stacks) contains(candidates ...stack.Stack) bool {
 	for _, v := range s {
-		if v == stack {
+		if v == AnyStack {
 			return true
 		}
+
+		for _, c := range candidates {
+			if v == c {
+				return true
+			}
+		}
 	}
 
 	return false
 }
+
+// rank returns the position of the first candidate stack that the collection names explicitly, so that a lower rank
+// is a more specific match.  A collection that is only compatible through the AnyStack wildcard ranks after every
+// candidate.
+func (s Stacks) rank(candidates ...stack.Stack) int {
+	for i, c := range candidates {
+		for _, v := range s {
+			if v == c {
+				return i
+			}
+		}
+	}
+
+	return len(candidates)
+}
@@ -33,5 +33,14 @@ func TestStacks(t *testing.T) {
 		it("does not validate if there is not at least one stack", func() {
 			g.Expect(buildpack.Stacks{}.Validate()).NotTo(gomega.Succeed())
 		})
+
+		it("validates wildcard stack", func() {
+			g.Expect(buildpack.Stacks{buildpack.AnyStack}.Validate()).To(gomega.Succeed())
+		})
+
+		it("does not validate if wildcard stack is combined with other stacks", func() {
+			g.Expect(buildpack.Stacks{buildpack.AnyStack, "test-stack"}.Validate()).
+				To(gomega.MatchError("stack * must not be combined with other stacks"))
+		})
 	}, spec.Report(report.Terminal{}))
 }
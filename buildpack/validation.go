@@ -0,0 +1,209 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Validate checks the buildpack metadata and reports all problems in a single error.  It checks that every dependency
+// is well-formed and valid, that dependency URIs are absolute, that no two dependencies share an id, version, stack,
+// os, and arch, that every default version, after resolving version-aliases, matches a dependency, and that
+// include_files is an array of strings.
+func (b Buildpack) Validate() error {
+	var problems []string
+
+	deps, p := b.validateDependencies()
+	problems = append(problems, p...)
+	problems = append(problems, b.validateDefaultVersions(deps)...)
+	problems = append(problems, b.validateIncludeFiles()...)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid buildpack metadata:\n%s", strings.Join(problems, "\n"))
+	}
+
+	return nil
+}
+
+func (b Buildpack) validateDependencies() (Dependencies, []string) {
+	raw, ok := b.Metadata[DependenciesMetadata]
+	if !ok {
+		return nil, nil
+	}
+
+	maps, ok := raw.([]map[string]interface{})
+	if !ok {
+		return nil, []string{fmt.Sprintf("%s must be an array of tables", DependenciesMetadata)}
+	}
+
+	var (
+		deps     Dependencies
+		problems []string
+		seen     = make(map[string]int)
+	)
+
+	for i, m := range maps {
+		name := fmt.Sprintf("%s[%d]", DependenciesMetadata, i)
+
+		d, err := NewDependency(m)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", name, err.Error()))
+			continue
+		}
+
+		if d.ID != "" && d.Version.Version != nil {
+			name = fmt.Sprintf("%s (%s %s)", name, d.ID, d.Version.Original())
+		}
+
+		for _, p := range d.problems() {
+			problems = append(problems, fmt.Sprintf("%s: %s", name, p))
+		}
+
+		if u, err := url.Parse(d.URI); d.URI != "" && (err != nil || u.Scheme == "") {
+			problems = append(problems, fmt.Sprintf("%s: uri %s is not an absolute URI", name, d.URI))
+		}
+
+		for _, s := range d.Stacks {
+			key := fmt.Sprintf("%s %s %s", d.ID, d.Version.Original(), s)
+			if d.OS != "" || d.Arch != "" {
+				key = fmt.Sprintf("%s %s/%s", key, d.OS, d.Arch)
+			}
+
+			if j, ok := seen[key]; ok {
+				problems = append(problems, fmt.Sprintf("%s: duplicates %s[%d] for %s", name, DependenciesMetadata, j, key))
+			} else {
+				seen[key] = i
+			}
+		}
+
+		deps = append(deps, d)
+	}
+
+	return deps, problems
+}
+
+func (b Buildpack) validateDefaultVersions(deps Dependencies) []string {
+	raw, ok := b.Metadata[DefaultVersions]
+	if !ok {
+		return nil
+	}
+
+	defaults, ok := raw.(map[string]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("%s must be a table", DefaultVersions)}
+	}
+
+	var ids []string
+	for id := range defaults {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var problems []string
+	for _, id := range ids {
+		version, ok := defaults[id].(string)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s.%s must be a string", DefaultVersions, id))
+			continue
+		}
+
+		resolved, err := b.resolveVersion(id, version)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s.%s: %s", DefaultVersions, id, err.Error()))
+			continue
+		}
+
+		constraint, err := NewConstraint(resolved)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s.%s: %s", DefaultVersions, id, err.Error()))
+			continue
+		}
+
+		found := false
+		for _, d := range deps {
+			if d.ID == id && constraint.Check(d.Version.Version) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s.%s: %s does not match any dependency", DefaultVersions, id, version))
+		}
+	}
+
+	return problems
+}
+
+func (b Buildpack) validateIncludeFiles() []string {
+	raw, ok := b.Metadata["include_files"]
+	if !ok {
+		return nil
+	}
+
+	files, ok := raw.([]interface{})
+	if !ok {
+		return []string{"include_files must be an array of strings"}
+	}
+
+	var problems []string
+	for i, f := range files {
+		if _, ok := f.(string); !ok {
+			problems = append(problems, fmt.Sprintf("include_files[%d] must be a string, got %v", i, f))
+		}
+	}
+
+	return problems
+}
+
+// problems returns every reason that the dependency is invalid.
+func (d Dependency) problems() []string {
+	var problems []string
+
+	if "" == d.ID {
+		problems = append(problems, "id is required")
+	}
+
+	if "" == d.Name {
+		problems = append(problems, "name is required")
+	}
+
+	if (Version{} == d.Version) {
+		problems = append(problems, "version is required")
+	}
+
+	if "" == d.URI {
+		problems = append(problems, "uri is required")
+	}
+
+	if "" == d.SHA256 {
+		problems = append(problems, "sha256 is required")
+	}
+
+	if err := d.Stacks.Validate(); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if err := d.Licenses.Validate(); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	return problems
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack_test
+
+import (
+	"testing"
+
+	bp "github.com/buildpacks/libbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestValidation(t *testing.T) {
+	spec.Run(t, "Validation", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		validDependency := func(id string, version string) map[string]interface{} {
+			return map[string]interface{}{
+				"id":       id,
+				"name":     "test-name",
+				"version":  version,
+				"uri":      "https://test.com/test-path",
+				"sha256":   "test-sha256",
+				"stacks":   []interface{}{"test-stack"},
+				"licenses": []map[string]interface{}{{"type": "Apache-2.0"}},
+			}
+		}
+
+		it("validates empty metadata", func() {
+			g.Expect(buildpack.Buildpack{}.Validate()).To(gomega.Succeed())
+		})
+
+		it("validates valid metadata", func() {
+			b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+				buildpack.DependenciesMetadata: []map[string]interface{}{
+					validDependency("test-id", "1.0"),
+					validDependency("test-id", "2.0"),
+				},
+				buildpack.DefaultVersions: map[string]interface{}{"test-id": "1.*"},
+				"include_files":           []interface{}{"buildpack.toml"},
+			}}}
+
+			g.Expect(b.Validate()).To(gomega.Succeed())
+		})
+
+		it("resolves version aliases of default versions", func() {
+			b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+				buildpack.DependenciesMetadata: []map[string]interface{}{
+					validDependency("test-id", "1.0"),
+					validDependency("test-other-id", "1.0"),
+				},
+				buildpack.DefaultVersions: map[string]interface{}{"test-id": "lts/*", "test-other-id": "lts/*"},
+				buildpack.VersionAliases: map[string]interface{}{
+					"test-id":       map[string]interface{}{"lts/*": "1.*"},
+					"test-other-id": map[string]interface{}{"lts/*": "2.*"},
+				},
+			}}}
+
+			g.Expect(b.Validate()).To(gomega.MatchError(`invalid buildpack metadata:
+default-versions.test-other-id: lts/* does not match any dependency`))
+		})
+
+		it("reports all problems", func() {
+			missing := validDependency("test-id", "3.0")
+			delete(missing, "sha256")
+			missing["uri"] = "test-path"
+
+			invalid := validDependency("test-id", "test-version")
+
+			b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+				buildpack.DependenciesMetadata: []map[string]interface{}{
+					validDependency("test-id", "1.0"),
+					validDependency("test-id", "1.0"),
+					missing,
+					invalid,
+				},
+				buildpack.DefaultVersions: map[string]interface{}{"test-id": "2.*", "test-other-id": 1},
+				"include_files":           []interface{}{"buildpack.toml", 1},
+			}}}
+
+			g.Expect(b.Validate()).To(gomega.MatchError(`invalid buildpack metadata:
+dependencies[1] (test-id 1.0): duplicates dependencies[0] for test-id 1.0 test-stack
+dependencies[2] (test-id 3.0): sha256 is required
+dependencies[2] (test-id 3.0): uri test-path is not an absolute URI
+dependencies[3]: 1 error(s) decoding:
+
+* error decoding 'version': invalid semantic version test-version
+default-versions.test-id: 2.* does not match any dependency
+default-versions.test-other-id must be a string
+include_files[1] must be a string, got 1`))
+		})
+
+		it("reports malformed dependencies", func() {
+			b := buildpack.Buildpack{Buildpack: bp.Buildpack{Metadata: bp.Metadata{
+				buildpack.DependenciesMetadata: []interface{}{"test-dependency"},
+			}}}
+
+			g.Expect(b.Validate()).To(gomega.MatchError("invalid buildpack metadata:\ndependencies must be an array of tables"))
+
+			_, err := b.Dependencies()
+			g.Expect(err).To(gomega.MatchError("dependencies must be an array of tables"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
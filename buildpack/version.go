@@ -19,34 +19,79 @@ package buildpack
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/Masterminds/semver"
 )
 
-// Version is an extension to semver.Version to make it marshalable.
+// Version is an extension to semver.Version to make it marshalable.  Versions that are not semantic versions are
+// interpreted using the VersionSchemes, but retain their original form.
 type Version struct {
 	*semver.Version
+
+	original string
 }
 
 // MarshalText makes Version satisfy the encoding.TextMarshaler interface.
 func (v Version) MarshalText() ([]byte, error) {
-	return []byte(v.Version.Original()), nil
+	return []byte(v.Original()), nil
+}
+
+// Original returns the version as it was originally specified.
+func (v Version) Original() string {
+	if v.original != "" {
+		return v.original
+	}
+
+	return v.Version.Original()
 }
 
 // UnmarshalText makes Version satisfy the encoding.TextUnmarshaler interface.
 func (v *Version) UnmarshalText(text []byte) error {
-	s := string(text)
-
-	w, err := semver.NewVersion(s)
+	w, err := parseVersion(string(text))
 	if err != nil {
-		return fmt.Errorf("invalid semantic version %s", s)
+		return err
 	}
 
-	v.Version = w
+	*v = w
 	return nil
 }
 
+// lessThan compares versions, ordering versions that are otherwise equal by their build metadata.
+func (v Version) lessThan(o Version) bool {
+	if !v.Version.Equal(o.Version) {
+		return v.Version.LessThan(o.Version)
+	}
+
+	a, errA := strconv.Atoi(v.Metadata())
+	b, errB := strconv.Atoi(o.Metadata())
+	if errA == nil && errB == nil {
+		return a < b
+	}
+
+	return v.Metadata() < o.Metadata()
+}
+
+func parseVersion(s string) (Version, error) {
+	if w, err := semver.NewVersion(s); err == nil {
+		return Version{Version: w}, nil
+	}
+
+	if n, ok := semanticVersion(s); ok {
+		if w, err := semver.NewVersion(n); err == nil {
+			return Version{Version: w, original: s}, nil
+		}
+	}
+
+	return Version{}, fmt.Errorf("invalid semantic version %s", s)
+}
+
 func unmarshalText(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to == reflect.TypeOf(time.Time{}) {
+		return unmarshalDate(data)
+	}
+
 	if from.Kind() != reflect.String {
 		return data, nil
 	}
@@ -55,10 +100,27 @@ func unmarshalText(from reflect.Type, to reflect.Type, data interface{}) (interf
 		return data, nil
 	}
 
-	w, err := semver.NewVersion(data.(string))
-	if err != nil {
-		return nil, fmt.Errorf("invalid semantic version %s", data)
+	return parseVersion(data.(string))
+}
+
+// unmarshalDate converts a TOML date or a string containing an RFC 3339 timestamp or a "2006-01-02" date to midnight
+// UTC on that day so that it survives a round-trip through layer metadata unchanged.
+func unmarshalDate(data interface{}) (interface{}, error) {
+	var t time.Time
+
+	switch d := data.(type) {
+	case time.Time:
+		t = d
+	case string:
+		var err error
+		if t, err = time.Parse(time.RFC3339, d); err != nil {
+			if t, err = time.Parse("2006-01-02", d); err != nil {
+				return nil, fmt.Errorf("invalid date %s", d)
+			}
+		}
+	default:
+		return data, nil
 	}
 
-	return Version{w}, nil
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
 }
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver"
+)
+
+// VersionScheme interprets versions that are not semantic versions as equivalent semantic versions so that they can be
+// compared and constrained.
+type VersionScheme interface {
+	// SemanticVersion returns the semantic version equivalent to a version and whether the version belongs to the
+	// scheme.
+	SemanticVersion(version string) (string, bool)
+}
+
+// VersionSchemes are the schemes, in order, that are used to interpret versions and exact version constraints that are
+// not semantic versions.  Buildpacks may add schemes before reading their dependencies.
+var VersionSchemes = []VersionScheme{JavaVersionScheme{}}
+
+// JavaVersionScheme interprets Java versions from before JEP 223, such as 1.8.0_242, as a semantic version with the
+// feature release as the major version, such as 8.0.242.  Later Java versions, such as 11.0.6+10, are already semantic
+// versions.
+type JavaVersionScheme struct{}
+
+var javaVersion = regexp.MustCompile(`^1\.([0-9]+)\.([0-9]+)_([0-9]+)$`)
+
+// SemanticVersion makes JavaVersionScheme satisfy the VersionScheme interface.
+func (JavaVersionScheme) SemanticVersion(version string) (string, bool) {
+	g := javaVersion.FindStringSubmatch(version)
+	if g == nil {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s.%s.%s", g[1], g[2], g[3]), true
+}
+
+func semanticVersion(version string) (string, bool) {
+	for _, s := range VersionSchemes {
+		if v, ok := s.SemanticVersion(version); ok {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// NewConstraint parses a version constraint.  A constraint that is not a semantic version constraint, but is a version
+// in one of the VersionSchemes, is an exact constraint on the equivalent semantic version.
+func NewConstraint(constraint string) (*semver.Constraints, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err == nil {
+		return c, nil
+	}
+
+	if v, ok := semanticVersion(strings.TrimSpace(constraint)); ok {
+		return semver.NewConstraint(v)
+	}
+
+	return nil, err
+}
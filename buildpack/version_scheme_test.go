@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpack_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestVersionScheme(t *testing.T) {
+	spec.Run(t, "VersionScheme", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		dependency := func(version string) buildpack.Dependency {
+			d, err := buildpack.NewDependency(map[string]interface{}{
+				"id":      "test-id",
+				"version": version,
+				"stacks":  []interface{}{"test-stack"},
+			})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			return d
+		}
+
+		when("JavaVersionScheme", func() {
+
+			it("interprets Java 8 versions", func() {
+				v, ok := buildpack.JavaVersionScheme{}.SemanticVersion("1.8.0_242")
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(v).To(gomega.Equal("8.0.242"))
+			})
+
+			it("does not interpret other versions", func() {
+				_, ok := buildpack.JavaVersionScheme{}.SemanticVersion("11.0.6+10")
+				g.Expect(ok).To(gomega.BeFalse())
+			})
+		})
+
+		it("retains original version", func() {
+			d := dependency("1.8.0_242")
+
+			g.Expect(d.Version.Original()).To(gomega.Equal("1.8.0_242"))
+			g.Expect(d.Version.Version.String()).To(gomega.Equal("8.0.242"))
+			g.Expect(d.Version.MarshalText()).To(gomega.Equal([]byte("1.8.0_242")))
+
+			var v buildpack.Version
+			g.Expect(v.UnmarshalText([]byte("1.8.0_242"))).To(gomega.Succeed())
+			g.Expect(v).To(gomega.Equal(d.Version))
+		})
+
+		it("returns error for versions outside of all schemes", func() {
+			var v buildpack.Version
+			g.Expect(v.UnmarshalText([]byte("test-version"))).To(gomega.MatchError("invalid semantic version test-version"))
+		})
+
+		it("resolves constraints against interpreted versions", func() {
+			d := buildpack.Dependencies{dependency("1.8.0_232"), dependency("1.8.0_242"), dependency("11.0.6+10")}
+
+			g.Expect(d.Best("test-id", "8.*", "test-stack")).To(gomega.Equal(dependency("1.8.0_242")))
+			g.Expect(d.Best("test-id", "1.8.0_232", "test-stack")).To(gomega.Equal(dependency("1.8.0_232")))
+		})
+
+		it("orders versions by build metadata", func() {
+			d := buildpack.Dependencies{dependency("11.0.6+10"), dependency("11.0.6+9")}
+
+			g.Expect(d.Best("test-id", "11.*", "test-stack")).To(gomega.Equal(dependency("11.0.6+10")))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpackplan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/buildpacks/libbuildpack/v2/stack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+)
+
+// ConstraintIntersection returns a VersionFunc that treats each requested version as a version constraint, such as ~1.2
+// or >=1.2.3, and selects the latest version of the dependency with a given id that satisfies every constraint and is
+// compatible with the stack.  The selected version is the version of the dependency as declared in the buildpack, so
+// that it can be passed directly to Dependencies.Best.  If no dependency satisfies every constraint, the error lists
+// each constraint and where it was requested.
+func ConstraintIntersection(dependencies buildpack.Dependencies, id string, stack stack.Stack) VersionFunc {
+	return func(requests []Request) (string, error) {
+		var constraints []*semver.Constraints
+
+		for _, r := range requests {
+			c, err := buildpack.NewConstraint(fmt.Sprintf("%v", r.Value))
+			if err != nil {
+				return "", fmt.Errorf("invalid version constraint %s for %s: %s", r, id, err.Error())
+			}
+
+			constraints = append(constraints, c)
+		}
+
+		var candidates buildpack.Dependencies
+		for _, d := range dependencies {
+			if d.ID == id && satisfiesAll(d.Version, constraints) {
+				candidates = append(candidates, d)
+			}
+		}
+
+		if len(candidates) == 0 {
+			return "", fmt.Errorf("no version of %s satisfies all of %s", id, describeRequests(requests))
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[j].Version.LessThan(candidates[i].Version.Version)
+		})
+
+		var err error
+		for _, c := range candidates {
+			var d buildpack.Dependency
+			if d, err = dependencies.Best(id, c.Version.Original(), stack); err == nil {
+				return d.Version.Original(), nil
+			}
+		}
+
+		return "", fmt.Errorf("no version of %s that satisfies all of %s is compatible with %s: %s",
+			id, describeRequests(requests), stack, err.Error())
+	}
+}
+
+// GetConstraintMerged returns a single Plan that is a merged version of all of the Plan's that have a given name.
+// Versions are merged with ConstraintIntersection and metadata is merged as described by Merger.  Returns true if any
+// matching Plan's were found, false otherwise.
+func (p Plans) GetConstraintMerged(name string, dependencies buildpack.Dependencies, stack stack.Stack) (Plan, bool, error) {
+	m, _, ok, err := p.GetMergedWithConflicts(name, Merger{Version: ConstraintIntersection(dependencies, name, stack)})
+	return m, ok, err
+}
+
+func satisfiesAll(version buildpack.Version, constraints []*semver.Constraints) bool {
+	for _, c := range constraints {
+		if !c.Check(version.Version) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func describeRequests(requests []Request) string {
+	var r []string
+	for _, q := range requests {
+		r = append(r, q.String())
+	}
+
+	return strings.Join(r, ", ")
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpackplan_test
+
+import (
+	"testing"
+
+	bp "github.com/buildpacks/libbuildpack/v2/buildpackplan"
+	"github.com/buildpacks/libbuildpack/v2/stack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/internal"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestConstraintMerge(t *testing.T) {
+	spec.Run(t, "ConstraintMerge", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			dependencies buildpack.Dependencies
+			version      buildpackplan.VersionFunc
+		)
+
+		requests := func(versions ...string) []buildpackplan.Request {
+			var r []buildpackplan.Request
+			for _, v := range versions {
+				r = append(r, buildpackplan.Request{Source: "test-source", Value: v})
+			}
+			return r
+		}
+
+		it.Before(func() {
+			dependency := func(version string, s stack.Stack) buildpack.Dependency {
+				return buildpack.Dependency{
+					ID:      "test-id",
+					Version: internal.NewTestVersion(t, version),
+					Stacks:  buildpack.Stacks{s},
+				}
+			}
+
+			dependencies = buildpack.Dependencies{
+				dependency("1.2.2", "test-stack"),
+				dependency("1.2.4", "test-stack"),
+				dependency("1.2.5", "other-stack"),
+				dependency("1.3.0", "test-stack"),
+				dependency("2.0.0", "test-stack"),
+			}
+
+			version = buildpackplan.ConstraintIntersection(dependencies, "test-id", "test-stack")
+		})
+
+		it("selects latest version satisfying all constraints", func() {
+			g.Expect(version(requests("~1.2", ">=1.2.3"))).To(gomega.Equal("1.2.4"))
+		})
+
+		it("supports alternative constraints", func() {
+			g.Expect(version(requests("~1.2 || ^2", ">=1.3"))).To(gomega.Equal("2.0.0"))
+		})
+
+		it("returns error when intersection is empty", func() {
+			_, err := version([]buildpackplan.Request{
+				{Source: "package.json", Value: "~1.2"},
+				{Source: "buildpack.yml", Value: ">=1.3"},
+			})
+
+			g.Expect(err).To(gomega.MatchError("no version of test-id satisfies all of ~1.2 (package.json), >=1.3 (buildpack.yml)"))
+		})
+
+		it("returns error when no satisfying version supports stack", func() {
+			_, err := version(requests("1.2.5"))
+
+			g.Expect(err).To(gomega.MatchError(gomega.HavePrefix(
+				"no version of test-id that satisfies all of 1.2.5 (test-source) is compatible with test-stack")))
+		})
+
+		it("returns error for invalid constraint", func() {
+			_, err := version(requests("test-version"))
+
+			g.Expect(err).To(gomega.MatchError(gomega.HavePrefix("invalid version constraint test-version (test-source) for test-id")))
+		})
+
+		it("merges plans", func() {
+			ps := buildpackplan.Plans{Plans: bp.Plans{Entries: []bp.Plan{
+				{Name: "test-id", Version: "~1.2", Metadata: bp.Metadata{"build": true}},
+				{Name: "test-id", Version: ">=1.2.3", Metadata: bp.Metadata{"launch": true}},
+			}}}
+
+			p, ok, err := ps.GetConstraintMerged("test-id", dependencies, "test-stack")
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(p).To(gomega.Equal(buildpackplan.Plan{
+				Name:     "test-id",
+				Version:  "1.2.4",
+				Metadata: buildpackplan.Metadata{"build": true, "launch": true},
+			}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,399 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpackplan
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Request is a value that a plan entry requested for a key.
+type Request struct {
+	// Source describes the entry that made the request.  It is the entry's version-source if it declares one, and its
+	// position among the merged entries otherwise.
+	Source string
+
+	// Value is the requested value.
+	Value interface{}
+}
+
+func (r Request) String() string {
+	return fmt.Sprintf("%v (%s)", r.Value, r.Source)
+}
+
+// Conflict describes a key for which plan entries requested values that could not be combined.
+type Conflict struct {
+	// Key is the conflicting key.  It is "version" for the version of the plan and the metadata key otherwise.
+	Key string
+
+	// Requests are the requests for the key, in entry order.
+	Requests []Request
+
+	// Resolved is the value used in the merged plan.
+	Resolved interface{}
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s requested as %s; using %v", c.Key, describeRequests(c.Requests), c.Resolved)
+}
+
+// VersionFunc selects the version of a merged plan from the versions requested by the merged entries.
+type VersionFunc func(requests []Request) (string, error)
+
+// Merger merges any number of plan entries without modifying them.
+//
+// Versions are selected by Version, if it is set.  Otherwise, the requests with the highest priority, as defined by the
+// version-source of the entry and Priorities, are considered and the highest version among them is selected.  The
+// version-source of the merged plan is the source of the selected version.
+//
+// build and launch are true if any entry requests them.  Other metadata values are combined by type: strings are joined
+// with a comma delimiter, lists are combined without duplicates, tables are merged key by key, and booleans are or-ed.
+// Any other differing values, such as numbers or values of different types, are reported as a Conflict and the last
+// value is used.
+type Merger struct {
+	// Priorities are the priority levels of version sources.  Unknown sources have a priority level of 0.
+	Priorities map[interface{}]int
+
+	// Version selects the merged version.  If it is nil, versions are selected by priority.
+	Version VersionFunc
+}
+
+// Merge merges plan entries into a single plan, returning any conflicts between the entries.
+func (m Merger) Merge(plans ...Plan) (Plan, []Conflict, error) {
+	if len(plans) == 0 {
+		return Plan{}, nil, nil
+	}
+
+	var conflicts []Conflict
+
+	version, source, conflict, err := m.version(plans)
+	if err != nil {
+		return Plan{}, nil, err
+	}
+	if conflict != nil {
+		conflicts = append(conflicts, *conflict)
+	}
+
+	metadata, c, err := m.metadata(plans)
+	if err != nil {
+		return Plan{}, nil, err
+	}
+	conflicts = append(conflicts, c...)
+
+	if source != nil && source != "" {
+		if metadata == nil {
+			metadata = make(Metadata)
+		}
+		metadata[VersionSource] = source
+	}
+
+	return Plan{Name: plans[0].Name, Version: version, Metadata: metadata}, conflicts, nil
+}
+
+func (m Merger) version(plans []Plan) (string, interface{}, *Conflict, error) {
+	var (
+		requests []Request
+		sources  []interface{}
+	)
+
+	for i, p := range plans {
+		if p.Version == "" {
+			continue
+		}
+
+		requests = append(requests, Request{Source: requestSource(p, i), Value: p.Version})
+		sources = append(sources, p.Metadata[VersionSource])
+	}
+
+	if len(requests) == 0 {
+		return "", nil, nil, nil
+	}
+
+	var (
+		version string
+		err     error
+	)
+
+	if m.Version != nil {
+		version, err = m.Version(requests)
+	} else {
+		version, err = m.priorityVersion(requests, sources)
+	}
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	var source interface{}
+	for i, r := range requests {
+		if r.Value == version {
+			source = sources[i]
+			break
+		}
+	}
+
+	for _, r := range requests[1:] {
+		if r.Value != requests[0].Value {
+			return version, source, &Conflict{Key: "version", Requests: requests, Resolved: version}, nil
+		}
+	}
+
+	return version, source, nil, nil
+}
+
+func (m Merger) priorityVersion(requests []Request, sources []interface{}) (string, error) {
+	priority := getPriority(sources[0], m.Priorities)
+	version := requests[0].Value.(string)
+
+	for i, r := range requests[1:] {
+		p := getPriority(sources[i+1], m.Priorities)
+		v := r.Value.(string)
+
+		if p > priority {
+			priority, version = p, v
+		} else if p == priority {
+			h, err := getHighestVersion(version, v)
+			if err != nil {
+				return "", fmt.Errorf("failed to get the highest version between %s and %s: %v", version, v, err)
+			}
+			version = h
+		}
+	}
+
+	return version, nil
+}
+
+func (m Merger) metadata(plans []Plan) (Metadata, []Conflict, error) {
+	keys := make(map[string]bool)
+	for _, p := range plans {
+		for k := range p.Metadata {
+			keys[k] = true
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, nil, nil
+	}
+
+	var sorted []string
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	metadata := make(Metadata)
+	var conflicts []Conflict
+
+	for _, k := range sorted {
+		if k == VersionSource {
+			continue
+		}
+
+		if k == "build" || k == "launch" {
+			v, err := anyTrue(plans, k)
+			if err != nil {
+				return nil, nil, err
+			}
+			if v {
+				metadata[k] = true
+			}
+			continue
+		}
+
+		var (
+			requests []Request
+			value    interface{}
+			conflict bool
+		)
+
+		for i, p := range plans {
+			v, ok := p.Metadata[k]
+			if !ok {
+				continue
+			}
+
+			requests = append(requests, Request{Source: requestSource(p, i), Value: v})
+
+			var combined bool
+			value, combined = mergeValue(value, v)
+			conflict = conflict || !combined
+		}
+
+		if value != nil {
+			metadata[k] = value
+		}
+
+		if conflict {
+			conflicts = append(conflicts, Conflict{Key: k, Requests: requests, Resolved: value})
+		}
+	}
+
+	return metadata, conflicts, nil
+}
+
+// GetMergedWithConflicts returns a single Plan that is a merged version of all of the Plan's that have a given name,
+// and any conflicts between them.  Merging is accomplished with the Merger.  Returns true if any matching Plan's were
+// found, false otherwise.
+func (p Plans) GetMergedWithConflicts(name string, merger Merger) (Plan, []Conflict, bool, error) {
+	plans := p.Get(name)
+	if len(plans) == 0 {
+		return Plan{}, nil, false, nil
+	}
+
+	m, c, err := merger.Merge(plans...)
+	if err != nil {
+		return Plan{}, nil, false, err
+	}
+
+	return m, c, true, nil
+}
+
+func anyTrue(plans []Plan, key string) (bool, error) {
+	result := false
+
+	for _, p := range plans {
+		v, err := metadataBool(p, key)
+		if err != nil {
+			return false, err
+		}
+		result = result || v
+	}
+
+	return result, nil
+}
+
+func requestSource(plan Plan, index int) string {
+	if s, ok := plan.Metadata[VersionSource]; ok && s != nil && s != "" {
+		return fmt.Sprintf("%v", s)
+	}
+
+	return fmt.Sprintf("entry %d", index)
+}
+
+// mergeValue combines two values without modifying either of them.  Returns false if the values could not be combined,
+// in which case b is returned.
+func mergeValue(a, b interface{}) (interface{}, bool) {
+	if a == nil || a == "" {
+		return copyValue(b), true
+	}
+
+	if b == nil || b == "" {
+		return copyValue(a), true
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return copyValue(a), true
+	}
+
+	switch av := a.(type) {
+	case string:
+		if bv, ok := b.(string); ok {
+			return mergeStrings(av, bv), true
+		}
+	case bool:
+		if bv, ok := b.(bool); ok {
+			return av || bv, true
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			return mergeLists(av, bv), true
+		}
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			return mergeTables(av, bv)
+		}
+	case Metadata:
+		if bv, ok := b.(Metadata); ok {
+			m, combined := mergeTables(av, bv)
+			return Metadata(m), combined
+		}
+	}
+
+	return copyValue(b), false
+}
+
+func mergeStrings(a, b string) string {
+	values := strings.Split(a, ",")
+
+	for _, v := range strings.Split(b, ",") {
+		if !contains(values, v) {
+			values = append(values, v)
+		}
+	}
+
+	return strings.Join(values, ",")
+}
+
+func mergeLists(a, b []interface{}) []interface{} {
+	m := copyValue(a).([]interface{})
+
+	for _, v := range b {
+		found := false
+		for _, e := range m {
+			if reflect.DeepEqual(e, v) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			m = append(m, copyValue(v))
+		}
+	}
+
+	return m
+}
+
+func mergeTables(a, b map[string]interface{}) (map[string]interface{}, bool) {
+	m := copyValue(a).(map[string]interface{})
+	combined := true
+
+	for k, v := range b {
+		var ok bool
+		m[k], ok = mergeValue(m[k], v)
+		combined = combined && ok
+	}
+
+	return m, combined
+}
+
+// copyValue returns a deep copy of lists and tables so that merged plans never share state with their inputs.
+func copyValue(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case []interface{}:
+		c := make([]interface{}, len(vv))
+		for i, e := range vv {
+			c[i] = copyValue(e)
+		}
+		return c
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(vv))
+		for k, e := range vv {
+			c[k] = copyValue(e)
+		}
+		return c
+	case Metadata:
+		c := make(Metadata, len(vv))
+		for k, e := range vv {
+			c[k] = copyValue(e)
+		}
+		return c
+	default:
+		return v
+	}
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpackplan_test
+
+import (
+	"testing"
+
+	bp "github.com/buildpacks/libbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestMerge(t *testing.T) {
+	spec.Run(t, "Merge", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		merger := buildpackplan.Merger{Priorities: map[interface{}]int{"buildpack.yml": 2, "package.json": 1}}
+
+		it("returns empty plan without entries", func() {
+			p, c, err := merger.Merge()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p).To(gomega.Equal(buildpackplan.Plan{}))
+			g.Expect(c).To(gomega.BeEmpty())
+		})
+
+		it("selects version by priority and reports conflict", func() {
+			p, c, err := merger.Merge(
+				buildpackplan.Plan{Name: "test", Version: "2.0", Metadata: buildpackplan.Metadata{buildpackplan.VersionSource: "package.json"}},
+				buildpackplan.Plan{Name: "test", Version: "1.0", Metadata: buildpackplan.Metadata{buildpackplan.VersionSource: "buildpack.yml"}},
+				buildpackplan.Plan{Name: "test", Version: "3.0"},
+			)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(p).To(gomega.Equal(buildpackplan.Plan{
+				Name:     "test",
+				Version:  "1.0",
+				Metadata: buildpackplan.Metadata{buildpackplan.VersionSource: "buildpack.yml"},
+			}))
+			g.Expect(c).To(gomega.HaveLen(1))
+			g.Expect(c[0].String()).To(gomega.Equal(
+				"version requested as 2.0 (package.json), 1.0 (buildpack.yml), 3.0 (entry 2); using 1.0"))
+		})
+
+		it("selects highest version with equal priority", func() {
+			p, _, err := merger.Merge(
+				buildpackplan.Plan{Name: "test", Version: "1.0"},
+				buildpackplan.Plan{Name: "test", Version: "2.0"},
+			)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Version).To(gomega.Equal("2.0"))
+		})
+
+		it("does not report conflict for equal versions", func() {
+			_, c, err := merger.Merge(
+				buildpackplan.Plan{Name: "test", Version: "1.0"},
+				buildpackplan.Plan{Name: "test", Version: "1.0"},
+				buildpackplan.Plan{Name: "test"},
+			)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(c).To(gomega.BeEmpty())
+		})
+
+		it("uses version function", func() {
+			m := buildpackplan.Merger{Version: func(requests []buildpackplan.Request) (string, error) {
+				return "test-version", nil
+			}}
+
+			p, _, err := m.Merge(
+				buildpackplan.Plan{Name: "test", Version: "1.0"},
+				buildpackplan.Plan{Name: "test", Version: "2.0"},
+			)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Version).To(gomega.Equal("test-version"))
+		})
+
+		it("merges metadata by type", func() {
+			p, c, err := merger.Merge(
+				buildpackplan.Plan{Name: "test", Metadata: buildpackplan.Metadata{
+					"build":  "true",
+					"string": "a",
+					"list":   []interface{}{"a", "b"},
+					"table":  map[string]interface{}{"alpha": "a", "bravo": true},
+					"bool":   false,
+				}},
+				buildpackplan.Plan{Name: "test", Metadata: buildpackplan.Metadata{
+					"launch": true,
+					"string": "b",
+					"list":   []interface{}{"b", "c"},
+					"table":  map[string]interface{}{"alpha": "b", "charlie": 1},
+					"bool":   true,
+				}},
+			)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(p.Metadata).To(gomega.Equal(buildpackplan.Metadata{
+				"build":  true,
+				"launch": true,
+				"string": "a,b",
+				"list":   []interface{}{"a", "b", "c"},
+				"table":  map[string]interface{}{"alpha": "a,b", "bravo": true, "charlie": 1},
+				"bool":   true,
+			}))
+			g.Expect(c).To(gomega.BeEmpty())
+		})
+
+		it("reports metadata that cannot be combined", func() {
+			p, c, err := merger.Merge(
+				buildpackplan.Plan{Name: "test", Metadata: buildpackplan.Metadata{"number": 1}},
+				buildpackplan.Plan{Name: "test", Metadata: buildpackplan.Metadata{"number": 2, buildpackplan.VersionSource: "package.json"}},
+			)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(p.Metadata["number"]).To(gomega.Equal(2))
+			g.Expect(c).To(gomega.ConsistOf(buildpackplan.Conflict{
+				Key: "number",
+				Requests: []buildpackplan.Request{
+					{Source: "entry 0", Value: 1},
+					{Source: "package.json", Value: 2},
+				},
+				Resolved: 2,
+			}))
+		})
+
+		it("does not modify inputs", func() {
+			a := buildpackplan.Plan{Name: "test", Metadata: buildpackplan.Metadata{
+				"list":  []interface{}{"a"},
+				"table": map[string]interface{}{"alpha": "a"},
+			}}
+			b := buildpackplan.Plan{Name: "test", Metadata: buildpackplan.Metadata{
+				"list":  []interface{}{"b"},
+				"table": map[string]interface{}{"alpha": "b"},
+			}}
+
+			p, _, err := merger.Merge(a, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			p.Metadata["table"].(map[string]interface{})["alpha"] = "c"
+
+			g.Expect(a.Metadata).To(gomega.Equal(buildpackplan.Metadata{
+				"list":  []interface{}{"a"},
+				"table": map[string]interface{}{"alpha": "a"},
+			}))
+			g.Expect(b.Metadata).To(gomega.Equal(buildpackplan.Metadata{
+				"list":  []interface{}{"b"},
+				"table": map[string]interface{}{"alpha": "b"},
+			}))
+		})
+
+		it("returns error for invalid build metadata", func() {
+			_, _, err := merger.Merge(buildpackplan.Plan{Name: "test", Metadata: buildpackplan.Metadata{"build": "test-value"}})
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		when("GetMergedWithConflicts", func() {
+
+			ps := buildpackplan.Plans{Plans: bp.Plans{Entries: []bp.Plan{
+				{Name: "test-entry-1", Version: "1.0"},
+				{Name: "test-entry-2", Version: "2.0"},
+				{Name: "test-entry-1", Version: "3.0"},
+			}}}
+
+			it("merges matching entries", func() {
+				p, c, ok, err := ps.GetMergedWithConflicts("test-entry-1", merger)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(p).To(gomega.Equal(buildpackplan.Plan{Name: "test-entry-1", Version: "3.0"}))
+				g.Expect(c).To(gomega.HaveLen(1))
+			})
+
+			it("returns false if no matches", func() {
+				_, _, ok, err := ps.GetMergedWithConflicts("test-entry-3", merger)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(ok).To(gomega.BeFalse())
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
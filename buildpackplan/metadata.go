@@ -0,0 +1,77 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpackplan
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// DecodeMetadata decodes the metadata of a plan into a value, typically a pointer to a struct whose fields are tagged
+// with `mapstruct:"<key>"`.  Decoding is weakly typed so that strings, booleans, and numbers are converted to the type of
+// the field, for example "true" to true and "1" to 1.
+func DecodeMetadata(plan Plan, value interface{}) error {
+	d, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           value,
+		TagName:          "mapstruct",
+		WeaklyTypedInput: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := d.Decode(map[string]interface{}(plan.Metadata)); err != nil {
+		return fmt.Errorf("invalid metadata for %s: %s", plan.Name, err.Error())
+	}
+
+	return nil
+}
+
+// IsBuild returns whether a plan requests its contribution to be available during build.
+func IsBuild(plan Plan) (bool, error) {
+	return metadataBool(plan, "build")
+}
+
+// IsLaunch returns whether a plan requests its contribution to be available during launch.
+func IsLaunch(plan Plan) (bool, error) {
+	return metadataBool(plan, "launch")
+}
+
+// GetVersionSource returns where the version of a plan was requested, or an empty string if no source is declared.
+func GetVersionSource(plan Plan) (string, error) {
+	var m struct {
+		VersionSource string `mapstruct:"version-source"`
+	}
+
+	if err := DecodeMetadata(plan, &m); err != nil {
+		return "", err
+	}
+
+	return m.VersionSource, nil
+}
+
+func metadataBool(plan Plan, key string) (bool, error) {
+	var v bool
+
+	if err := mapstructure.WeakDecode(plan.Metadata[key], &v); err != nil {
+		return false, fmt.Errorf("could not determine '%s' metadata of %s: could not get boolean value of %v",
+			key, plan.Name, plan.Metadata[key])
+	}
+
+	return v, nil
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package buildpackplan_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestMetadata(t *testing.T) {
+	spec.Run(t, "Metadata", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		type metadata struct {
+			Enabled bool     `mapstruct:"enabled"`
+			Count   int      `mapstruct:"count"`
+			Name    string   `mapstruct:"name"`
+			Paths   []string `mapstruct:"paths"`
+		}
+
+		it("decodes metadata with weak typing", func() {
+			var m metadata
+			g.Expect(buildpackplan.DecodeMetadata(buildpackplan.Plan{Name: "test", Metadata: buildpackplan.Metadata{
+				"enabled": "true",
+				"count":   "2",
+				"name":    3,
+				"paths":   []interface{}{"test-path"},
+			}}, &m)).To(gomega.Succeed())
+
+			g.Expect(m).To(gomega.Equal(metadata{Enabled: true, Count: 2, Name: "3", Paths: []string{"test-path"}}))
+		})
+
+		it("returns error for invalid metadata", func() {
+			var m metadata
+			err := buildpackplan.DecodeMetadata(buildpackplan.Plan{Name: "test", Metadata: buildpackplan.Metadata{
+				"count": "test-value",
+			}}, &m)
+
+			g.Expect(err).To(gomega.MatchError(gomega.HavePrefix("invalid metadata for test:")))
+		})
+
+		it("decodes build and launch", func() {
+			p := buildpackplan.Plan{Name: "test", Metadata: buildpackplan.Metadata{"build": "true", "launch": false}}
+
+			g.Expect(buildpackplan.IsBuild(p)).To(gomega.BeTrue())
+			g.Expect(buildpackplan.IsLaunch(p)).To(gomega.BeFalse())
+		})
+
+		it("defaults build and launch to false", func() {
+			g.Expect(buildpackplan.IsBuild(buildpackplan.Plan{Name: "test"})).To(gomega.BeFalse())
+		})
+
+		it("returns error for invalid build", func() {
+			_, err := buildpackplan.IsBuild(buildpackplan.Plan{Name: "test", Metadata: buildpackplan.Metadata{"build": "test-value"}})
+
+			g.Expect(err).To(gomega.MatchError("could not determine 'build' metadata of test: could not get boolean value of test-value"))
+		})
+
+		it("decodes version source", func() {
+			p := buildpackplan.Plan{Name: "test", Metadata: buildpackplan.Metadata{buildpackplan.VersionSource: "buildpack.yml"}}
+
+			g.Expect(buildpackplan.GetVersionSource(p)).To(gomega.Equal("buildpack.yml"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
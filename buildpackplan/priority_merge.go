@@ -34,9 +34,9 @@ const VersionSource = "version-source"
 //   "package.json":  2,
 //   ".nvmrc":        1,
 //   "":              -1
-// Metadata for most cases is combined (excluding version-source, build and launch), with a comma delimiter if present
-// in both plans. version-source is set to the highest priority between the plans, and build/launch will be set to true
-// if either of the plans request them.
+// Metadata for most cases is combined (excluding version-source, build and launch) as described by Merger, without
+// modifying either plan. version-source is set to the highest priority between the plans, and build/launch will be set
+// to true if either of the plans request them.
 func PriorityMerge(priorities map[interface{}]int) MergeFunc {
 	return func(a, b Plan) (Plan, error) {
 		aVersion := a.Version
@@ -116,14 +116,11 @@ func mergePlans(a, b Plan, version string, versionSource interface{}) (Plan, err
 		return Plan{}, fmt.Errorf("could not determine 'launch' metadata of %s: %s", b.Name, err)
 	}
 
-	metadata := a.Metadata // NOTE: Mutating metadata also mutates a.Metadata
+	metadata := copyValue(a.Metadata).(Metadata)
 	for key, val := range b.Metadata {
 		ignoreKeys := []string{VersionSource, "build", "launch"}
 		if !contains(ignoreKeys, key) && val != "" {
-			if aVal, ok := metadata[key]; ok && aVal != "" && aVal != val {
-				val = aVal.(string) + "," + val.(string)
-			}
-			metadata[key] = val
+			metadata[key], _ = mergeValue(metadata[key], val)
 		}
 	}
 
@@ -137,6 +137,17 @@ func testPriorityMerge(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("metadata is not a string", func() {
+		it("merges without modifying the inputs", func() {
+			planA := createTestBuildPlan("", buildpackplan.Metadata{"key": []interface{}{"a"}})
+			planB := createTestBuildPlan("", buildpackplan.Metadata{"key": []interface{}{"b"}})
+			expected := createTestBuildPlan("", buildpackplan.Metadata{"key": []interface{}{"a", "b"}})
+			testPriorityMerge(planA, planB, expected)
+
+			Expect(planA.Metadata).To(Equal(buildpackplan.Metadata{"key": []interface{}{"a"}}))
+		})
+	})
+
 }
 
 func createTestBuildPlan(version string, metadata buildpackplan.Metadata) buildpackplan.Plan {
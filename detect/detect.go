@@ -17,6 +17,8 @@
 package detect
 
 import (
+	"path/filepath"
+
 	"github.com/buildpacks/libbuildpack/v2/detect"
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
 	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
@@ -39,6 +41,8 @@ type Detect struct {
 
 	// Services represents the services bound to the application.
 	Services services.Services
+
+	explanation *Explanation
 }
 
 // DefaultDetect creates a new instance of Detect using default values.  During initialization, all platform environment
@@ -51,6 +55,7 @@ func DefaultDetect() (Detect, error) {
 
 	logger := logger.Logger{Logger: d.Logger}
 	buildpack := buildpack.NewBuildpack(d.Buildpack, logger)
+	buildpack.BuildpackYAML = filepath.Join(d.Application.Root, "buildpack.yml")
 	services := services.Services{Services: d.Services}
 
 	return Detect{
@@ -59,5 +64,6 @@ func DefaultDetect() (Detect, error) {
 		logger,
 		runner.CommandRunner{},
 		services,
+		nil,
 	}, nil
 }
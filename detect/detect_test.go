@@ -109,32 +109,32 @@ test-key = "test-value"
 
 			g.Expect(d.Pass(buildplan.Plan{
 				Provides: []buildplan.Provided{
-					{"test-provided-1a"},
-					{"test-provided-1b"},
+					{Name: "test-provided-1a"},
+					{Name: "test-provided-1b"},
 				},
 				Requires: []buildplan.Required{
-					{"test-required-1a", "test-version-1a", buildplan.Metadata{"test-key-1a": "test-value-1a"}},
-					{"test-required-1b", "test-version-1b", buildplan.Metadata{"test-key-1b": "test-value-1b"}},
+					{Name: "test-required-1a", Version: "test-version-1a", Metadata: buildplan.Metadata{"test-key-1a": "test-value-1a"}},
+					{Name: "test-required-1b", Version: "test-version-1b", Metadata: buildplan.Metadata{"test-key-1b": "test-value-1b"}},
 				},
 			},
 				buildplan.Plan{
 					Provides: []buildplan.Provided{
-						{"test-provided-2a"},
-						{"test-provided-2b"},
+						{Name: "test-provided-2a"},
+						{Name: "test-provided-2b"},
 					},
 					Requires: []buildplan.Required{
-						{"test-required-2a", "test-version-2a", buildplan.Metadata{"test-key-2a": "test-value-2a"}},
-						{"test-required-2b", "test-version-2b", buildplan.Metadata{"test-key-2b": "test-value-2b"}},
+						{Name: "test-required-2a", Version: "test-version-2a", Metadata: buildplan.Metadata{"test-key-2a": "test-value-2a"}},
+						{Name: "test-required-2b", Version: "test-version-2b", Metadata: buildplan.Metadata{"test-key-2b": "test-value-2b"}},
 					},
 				},
 				buildplan.Plan{
 					Provides: []buildplan.Provided{
-						{"test-provided-3a"},
-						{"test-provided-3b"},
+						{Name: "test-provided-3a"},
+						{Name: "test-provided-3b"},
 					},
 					Requires: []buildplan.Required{
-						{"test-required-3a", "test-version-3a", buildplan.Metadata{"test-key-3a": "test-value-3a"}},
-						{"test-required-3b", "test-version-3b", buildplan.Metadata{"test-key-3b": "test-value-3b"}},
+						{Name: "test-required-3a", Version: "test-version-3a", Metadata: buildplan.Metadata{"test-key-3a": "test-value-3a"}},
+						{Name: "test-required-3b", Version: "test-version-3b", Metadata: buildplan.Metadata{"test-key-3b": "test-value-3b"}},
 					},
 				})).To(gomega.Equal(detect.PassStatusCode))
 
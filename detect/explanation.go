@@ -0,0 +1,128 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detect
+
+import (
+	"fmt"
+
+	"github.com/buildpacks/libbuildpack/v2/buildplan"
+)
+
+// Reason is the outcome of a single detection check.
+type Reason struct {
+	// Check describes what was checked.
+	Check string
+
+	// Passed indicates whether the check passed.
+	Passed bool
+}
+
+func (r Reason) String() string {
+	if r.Passed {
+		return fmt.Sprintf("%s: passed", r.Check)
+	}
+
+	return fmt.Sprintf("%s: failed", r.Check)
+}
+
+// Explanation collects the reasons that detection checks passed or failed, and reports them when detection completes.
+// Reasons are reported on failure so that users can see why a buildpack did not participate in a build, and at debug
+// level on success.
+type Explanation struct {
+	detect  Detect
+	reasons []Reason
+}
+
+// Explain returns an Explanation for detection.  Rules evaluated with the Explanation record a reason for each check.
+func (d Detect) Explain() *Explanation {
+	e := &Explanation{}
+
+	d.explanation = e
+	e.detect = d
+
+	return e
+}
+
+// Check records the outcome of a check and returns whether it passed.
+func (e *Explanation) Check(check string, passed bool) bool {
+	e.reasons = append(e.reasons, Reason{Check: check, Passed: passed})
+	return passed
+}
+
+// Evaluate evaluates a rule, recording the outcome of each check that it evaluates.
+func (e *Explanation) Evaluate(rule Rule) (bool, error) {
+	return e.detect.Evaluate(rule)
+}
+
+// Reasons returns the recorded reasons, in the order the checks were made.
+func (e *Explanation) Reasons() []Reason {
+	return e.reasons
+}
+
+// Summary returns a one-line summary of the recorded reasons.
+func (e *Explanation) Summary() string {
+	passed := 0
+	for _, r := range e.reasons {
+		if r.Passed {
+			passed++
+		}
+	}
+
+	return fmt.Sprintf("%d of %d checks passed", passed, len(e.reasons))
+}
+
+// Pass signals a successful detection with the plans, logging the recorded reasons at debug level.
+func (e *Explanation) Pass(plans ...buildplan.Plan) (int, error) {
+	e.detect.Logger.Debug("Detection passed: %s", e.Summary())
+	for _, r := range e.reasons {
+		e.detect.Logger.Debug("  %s", r)
+	}
+
+	return e.detect.Pass(plans...)
+}
+
+// Fail signals an unsuccessful detection, logging the recorded reasons.
+func (e *Explanation) Fail() int {
+	e.detect.Logger.Title(e.detect.Buildpack)
+	e.detect.Logger.Header("Detection failed: %s", e.Summary())
+	for _, r := range e.reasons {
+		e.detect.Logger.Body(r.String())
+	}
+
+	return e.detect.Fail()
+}
+
+// PassIf passes detection with the plans if the rule matches, and fails detection otherwise.  In either case the
+// recorded reasons are logged as described by Pass and Fail.
+func (e *Explanation) PassIf(rule Rule, plans ...buildplan.Plan) (int, error) {
+	ok, err := e.Evaluate(rule)
+	if err != nil {
+		return -1, err
+	}
+
+	if !ok {
+		return e.Fail(), nil
+	}
+
+	return e.Pass(plans...)
+}
+
+func (e *Explanation) record(check string, passed bool) {
+	if e != nil {
+		e.Check(check, passed)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detect_test
+
+import (
+	"bytes"
+	"testing"
+
+	loggerBp "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/detect"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestExplanation(t *testing.T) {
+	spec.Run(t, "Explanation", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			debug bytes.Buffer
+			info  bytes.Buffer
+			f     *test.DetectFactory
+		)
+
+		it.Before(func() {
+			debug.Reset()
+			info.Reset()
+
+			f = test.NewDetectFactory(t)
+			f.Detect.Buildpack.Info.Name = "test-name"
+			f.Detect.Logger = logger.Logger{Logger: loggerBp.NewLogger(&debug, &info)}
+		})
+
+		it("records checks and rule outcomes", func() {
+			test.TouchFile(t, f.Detect.Application.Root, "test.csproj")
+
+			e := f.Detect.Explain()
+			g.Expect(e.Check("test-check", false)).To(gomega.BeFalse())
+			g.Expect(e.Evaluate(detect.Any(detect.FileGlob("*.fsproj"), detect.FileGlob("*.csproj")))).To(gomega.BeTrue())
+
+			g.Expect(e.Reasons()).To(gomega.Equal([]detect.Reason{
+				{Check: "test-check", Passed: false},
+				{Check: "file *.fsproj", Passed: false},
+				{Check: "file *.csproj", Passed: true},
+			}))
+			g.Expect(e.Summary()).To(gomega.Equal("1 of 3 checks passed"))
+		})
+
+		it("does not record rules evaluated without explanation", func() {
+			e := f.Detect.Explain()
+
+			_, err := f.Detect.Evaluate(detect.FileGlob("*.csproj"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Reasons()).To(gomega.BeEmpty())
+		})
+
+		it("logs reasons on failure", func() {
+			e := f.Detect.Explain()
+
+			g.Expect(e.PassIf(detect.FileGlob("*.csproj"))).To(gomega.Equal(detect.FailStatusCode))
+
+			g.Expect(info.String()).To(gomega.ContainSubstring("Detection failed: 0 of 1 checks passed"))
+			g.Expect(info.String()).To(gomega.ContainSubstring("file *.csproj: failed"))
+		})
+
+		it("logs reasons at debug level on success", func() {
+			test.TouchFile(t, f.Detect.Application.Root, "test.csproj")
+			e := f.Detect.Explain()
+
+			g.Expect(e.PassIf(detect.FileGlob("*.csproj"))).To(gomega.Equal(detect.PassStatusCode))
+
+			g.Expect(info.String()).To(gomega.BeEmpty())
+			g.Expect(debug.String()).To(gomega.ContainSubstring("Detection passed: 1 of 1 checks passed"))
+			g.Expect(debug.String()).To(gomega.ContainSubstring("file *.csproj: passed"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/buildpacks/libbuildpack/v2/buildplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+)
+
+// PlanBuilder builds the build plan, and any alternatives, that a buildpack passes detection with.
+type PlanBuilder struct {
+	detect   Detect
+	external map[string]bool
+	plans    []buildplan.Plan
+}
+
+// Plan returns a PlanBuilder for the primary build plan of the buildpack.
+func (d Detect) Plan() *PlanBuilder {
+	return &PlanBuilder{detect: d, external: make(map[string]bool), plans: []buildplan.Plan{{}}}
+}
+
+// Provide adds dependencies that the buildpack provides to the current plan.
+func (p *PlanBuilder) Provide(names ...string) *PlanBuilder {
+	c := p.current()
+
+	for _, n := range names {
+		c.Provides = append(c.Provides, buildplan.Provided{Name: n})
+	}
+
+	return p
+}
+
+// Require adds a dependency that the buildpack requires, without a version, to the current plan.
+func (p *PlanBuilder) Require(name string, metadata buildplan.Metadata) *PlanBuilder {
+	return p.RequireVersion(name, "", "", metadata)
+}
+
+// RequireVersion adds a dependency that the buildpack requires to the current plan.  If versionSource is not empty, it
+// is recorded as the version-source metadata of the requirement so that plans can be merged by priority.
+func (p *PlanBuilder) RequireVersion(name string, version string, versionSource string, metadata buildplan.Metadata) *PlanBuilder {
+	m := make(buildplan.Metadata, len(metadata))
+	for k, v := range metadata {
+		m[k] = v
+	}
+
+	if versionSource != "" {
+		m[buildpackplan.VersionSource] = versionSource
+	}
+
+	if len(m) == 0 {
+		m = nil
+	}
+
+	c := p.current()
+	c.Requires = append(c.Requires, buildplan.Required{Name: name, Version: version, Metadata: m})
+
+	return p
+}
+
+// RequireExternal adds a dependency that the buildpack requires, without a version, to the current plan and declares
+// that it is provided by another buildpack in the group rather than by the plan itself.
+func (p *PlanBuilder) RequireExternal(name string, metadata buildplan.Metadata) *PlanBuilder {
+	p.external[name] = true
+	return p.Require(name, metadata)
+}
+
+// External declares that dependencies are provided by other buildpacks in the group, so that requirements for them
+// are not checked against the provides of the plans.
+func (p *PlanBuilder) External(names ...string) *PlanBuilder {
+	for _, n := range names {
+		p.external[n] = true
+	}
+
+	return p
+}
+
+// Or starts an alternative plan.  Subsequent provides and requires are added to the alternative.
+func (p *PlanBuilder) Or() *PlanBuilder {
+	p.plans = append(p.plans, buildplan.Plan{})
+	return p
+}
+
+// Plans returns the primary plan followed by its alternatives.  Returns an error if any plan requires a dependency that
+// the same plan does not provide, unless the dependency is declared External.
+func (p *PlanBuilder) Plans() ([]buildplan.Plan, error) {
+	var problems []string
+
+	for i, plan := range p.plans {
+		provided := make(map[string]bool)
+		for _, d := range plan.Provides {
+			provided[d.Name] = true
+		}
+
+		for _, r := range plan.Requires {
+			if !provided[r.Name] && !p.external[r.Name] {
+				problems = append(problems,
+					fmt.Sprintf("plan %d requires %s, which it does not provide and is not external", i, r.Name))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("unsatisfiable build plan:\n%s", strings.Join(problems, "\n"))
+	}
+
+	return p.plans, nil
+}
+
+// Pass signals a successful detection with the built plans.  Returns an error, without passing, if the plans are not
+// satisfiable as described by Plans.
+func (p *PlanBuilder) Pass() (int, error) {
+	plans, err := p.Plans()
+	if err != nil {
+		return -1, err
+	}
+
+	return p.detect.Pass(plans...)
+}
+
+func (p *PlanBuilder) current() *buildplan.Plan {
+	return &p.plans[len(p.plans)-1]
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detect_test
+
+import (
+	"testing"
+
+	"github.com/buildpacks/libbuildpack/v2/buildplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/detect"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestPlanBuilder(t *testing.T) {
+	spec.Run(t, "PlanBuilder", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.DetectFactory
+
+		it.Before(func() {
+			f = test.NewDetectFactory(t)
+		})
+
+		it("passes with plan and alternatives", func() {
+			metadata := buildplan.Metadata{"launch": true}
+
+			g.Expect(f.Detect.Plan().
+				Provide("test-provided-1").
+				RequireVersion("test-provided-1", "1.2.3", "buildpack.yml", metadata).
+				Or().
+				Provide("test-provided-2").
+				Require("test-provided-2", nil).
+				Pass()).To(gomega.Equal(detect.PassStatusCode))
+
+			g.Expect(f.Plans).To(gomega.Equal(buildplan.Plans{
+				Plan: buildplan.Plan{
+					Provides: []buildplan.Provided{{Name: "test-provided-1"}},
+					Requires: []buildplan.Required{{
+						Name:     "test-provided-1",
+						Version:  "1.2.3",
+						Metadata: buildplan.Metadata{"launch": true, buildpackplan.VersionSource: "buildpack.yml"},
+					}},
+				},
+				Or: []buildplan.Plan{{
+					Provides: []buildplan.Provided{{Name: "test-provided-2"}},
+					Requires: []buildplan.Required{{Name: "test-provided-2"}},
+				}},
+			}))
+			g.Expect(metadata).To(gomega.Equal(buildplan.Metadata{"launch": true}))
+		})
+
+		it("passes with provides only", func() {
+			g.Expect(f.Detect.Plan().Provide("test-provided").Pass()).To(gomega.Equal(detect.PassStatusCode))
+		})
+
+		it("passes with requires provided by other buildpacks", func() {
+			g.Expect(f.Detect.Plan().
+				RequireExternal("jdk", buildplan.Metadata{"build": true}).
+				Pass()).To(gomega.Equal(detect.PassStatusCode))
+
+			g.Expect(f.Plans).To(gomega.Equal(buildplan.Plans{
+				Plan: buildplan.Plan{
+					Requires: []buildplan.Required{{Name: "jdk", Metadata: buildplan.Metadata{"build": true}}},
+				},
+			}))
+		})
+
+		it("passes with versioned requires declared external", func() {
+			g.Expect(f.Detect.Plan().
+				External("jdk").
+				RequireVersion("jdk", "11.*", "buildpack.yml", nil).
+				Pass()).To(gomega.Equal(detect.PassStatusCode))
+		})
+
+		it("does not pass when a require is not provided by the same plan", func() {
+			code, err := f.Detect.Plan().
+				Provide("test-provided-1").
+				Or().
+				Require("test-provided-1", nil).
+				Require("test-provided-2", nil).
+				Pass()
+
+			g.Expect(code).To(gomega.Equal(-1))
+			g.Expect(err).To(gomega.MatchError(`unsatisfiable build plan:
+plan 1 requires test-provided-1, which it does not provide and is not external
+plan 1 requires test-provided-2, which it does not provide and is not external`))
+			g.Expect(f.Plans).To(gomega.Equal(buildplan.Plans{}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,283 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/buildpacks/libbuildpack/v2/buildplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/manifest"
+	"gopkg.in/yaml.v2"
+)
+
+// Rule is a detection predicate over the application and its environment.  Rules are composed with All and Any.
+type Rule interface {
+	// Evaluate returns whether the rule matches.
+	Evaluate(detect Detect) (bool, error)
+
+	// String describes the rule.
+	String() string
+}
+
+// NewRule creates a Rule from a description and a predicate.
+func NewRule(description string, predicate func(detect Detect) (bool, error)) Rule {
+	return predicateRule{description, predicate}
+}
+
+// FileGlob returns a Rule that matches if the application contains a file matching a glob relative to the application
+// root.  * and ? do not match path separators, while ** matches any number of directories.
+func FileGlob(glob string) Rule {
+	return NewRule(fmt.Sprintf("file %s", glob), func(detect Detect) (bool, error) {
+		return helper.HasFile(detect.Application.Root, globPattern(detect.Application.Root, glob))
+	})
+}
+
+// JSONKey returns a Rule that matches if a JSON file, relative to the application root, contains a dotted key path.  A
+// file that is not valid JSON does not match.
+func JSONKey(file string, key string) Rule {
+	return documentKey("JSON", file, key, json.Unmarshal)
+}
+
+// TOMLKey returns a Rule that matches if a TOML file, relative to the application root, contains a dotted key path.  A
+// file that is not valid TOML does not match.
+func TOMLKey(file string, key string) Rule {
+	return documentKey("TOML", file, key, toml.Unmarshal)
+}
+
+// YAMLKey returns a Rule that matches if a YAML file, relative to the application root, contains a dotted key path.  A
+// file that is not valid YAML does not match.
+func YAMLKey(file string, key string) Rule {
+	return documentKey("YAML", file, key, yaml.Unmarshal)
+}
+
+// ManifestAttribute returns a Rule that matches if the application's META-INF/MANIFEST.MF contains an attribute.
+func ManifestAttribute(name string) Rule {
+	return NewRule(fmt.Sprintf("manifest attribute %s", name), func(detect Detect) (bool, error) {
+		m, err := manifest.NewManifest(detect.Application, detect.Logger)
+		if err != nil {
+			return false, err
+		}
+
+		_, ok := m.Get(name)
+		return ok, nil
+	})
+}
+
+// Service returns a Rule that matches if exactly one bound service matches a filter and has the required credentials,
+// as defined by services.Services.HasService.
+func Service(filter string, credentials ...string) Rule {
+	description := fmt.Sprintf("service %s", filter)
+	if len(credentials) > 0 {
+		description = fmt.Sprintf("%s with %s", description, strings.Join(credentials, ", "))
+	}
+
+	return NewRule(description, func(detect Detect) (bool, error) {
+		return detect.Services.HasService(filter, credentials...), nil
+	})
+}
+
+// EnvironmentVariable returns a Rule that matches if an environment variable is set.
+func EnvironmentVariable(name string) Rule {
+	return NewRule(fmt.Sprintf("$%s", name), func(detect Detect) (bool, error) {
+		_, ok := os.LookupEnv(name)
+		return ok, nil
+	})
+}
+
+// All returns a Rule that matches if every one of the rules matches.
+func All(rules ...Rule) Rule {
+	return allRule(rules)
+}
+
+// Any returns a Rule that matches if at least one of the rules matches.
+func Any(rules ...Rule) Rule {
+	return anyRule(rules)
+}
+
+// Evaluate evaluates a rule against the application, logging whether it matched at debug level.
+func (d Detect) Evaluate(rule Rule) (bool, error) {
+	ok, err := rule.Evaluate(d)
+	if err != nil {
+		return false, err
+	}
+
+	if ok {
+		d.Logger.Debug("Detection rule matched: %s", rule)
+	} else {
+		d.Logger.Debug("Detection rule did not match: %s", rule)
+	}
+
+	return ok, nil
+}
+
+// PassIf passes detection with the plans if the rule matches, and fails detection otherwise.
+func (d Detect) PassIf(rule Rule, plans ...buildplan.Plan) (int, error) {
+	ok, err := d.Evaluate(rule)
+	if err != nil {
+		return -1, err
+	}
+
+	if !ok {
+		return d.Fail(), nil
+	}
+
+	return d.Pass(plans...)
+}
+
+type predicateRule struct {
+	description string
+	predicate   func(detect Detect) (bool, error)
+}
+
+func (p predicateRule) Evaluate(detect Detect) (bool, error) {
+	ok, err := p.predicate(detect)
+	if err != nil {
+		return false, fmt.Errorf("unable to evaluate %s: %s", p.description, err.Error())
+	}
+
+	if ok {
+		detect.Logger.Debug("Matched %s", p.description)
+	}
+	detect.explanation.record(p.description, ok)
+
+	return ok, nil
+}
+
+func (p predicateRule) String() string {
+	return p.description
+}
+
+type allRule []Rule
+
+func (a allRule) Evaluate(detect Detect) (bool, error) {
+	for _, r := range a {
+		if ok, err := r.Evaluate(detect); err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (a allRule) String() string {
+	return joinRules(a, " and ")
+}
+
+type anyRule []Rule
+
+func (a anyRule) Evaluate(detect Detect) (bool, error) {
+	for _, r := range a {
+		if ok, err := r.Evaluate(detect); err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
+
+func (a anyRule) String() string {
+	return joinRules(a, " or ")
+}
+
+func joinRules(rules []Rule, separator string) string {
+	var s []string
+	for _, r := range rules {
+		s = append(s, r.String())
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(s, separator))
+}
+
+func documentKey(format string, file string, key string, unmarshal func([]byte, interface{}) error) Rule {
+	return NewRule(fmt.Sprintf("%s key %s in %s", format, key, file), func(detect Detect) (bool, error) {
+		path := filepath.Join(detect.Application.Root, file)
+
+		if exists, err := helper.FileExists(path); err != nil {
+			return false, err
+		} else if !exists {
+			return false, nil
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+
+		var document interface{}
+		if err := unmarshal(b, &document); err != nil {
+			detect.Logger.Debug("Unable to parse %s as %s: %s", file, format, err.Error())
+			return false, nil
+		}
+
+		return hasKey(document, strings.Split(key, ".")), nil
+	})
+}
+
+func hasKey(document interface{}, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+
+	var (
+		v  interface{}
+		ok bool
+	)
+
+	switch d := document.(type) {
+	case map[string]interface{}:
+		v, ok = d[path[0]]
+	case map[interface{}]interface{}:
+		v, ok = d[path[0]]
+	}
+
+	return ok && hasKey(v, path[1:])
+}
+
+func globPattern(root string, glob string) *regexp.Regexp {
+	var p strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					p.WriteString("(.*/)?")
+				} else {
+					p.WriteString(".*")
+				}
+			} else {
+				p.WriteString("[^/]*")
+			}
+		case '?':
+			p.WriteString("[^/]")
+		default:
+			p.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return regexp.MustCompile(fmt.Sprintf("^%s/%s$", regexp.QuoteMeta(filepath.Clean(root)), p.String()))
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package detect_test
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/buildpacks/libbuildpack/v2/buildplan"
+	loggerBp "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/detect"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/services"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestRules(t *testing.T) {
+	spec.Run(t, "Rules", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.DetectFactory
+
+		it.Before(func() {
+			f = test.NewDetectFactory(t)
+		})
+
+		evaluate := func(rule detect.Rule) bool {
+			ok, err := f.Detect.Evaluate(rule)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			return ok
+		}
+
+		when("FileGlob", func() {
+
+			it("matches file in root", func() {
+				test.TouchFile(t, f.Detect.Application.Root, "test.csproj")
+
+				g.Expect(evaluate(detect.FileGlob("*.csproj"))).To(gomega.BeTrue())
+				g.Expect(evaluate(detect.FileGlob("*.fsproj"))).To(gomega.BeFalse())
+			})
+
+			it("does not match nested file with single wildcard", func() {
+				test.TouchFile(t, f.Detect.Application.Root, "src", "test.csproj")
+
+				g.Expect(evaluate(detect.FileGlob("*.csproj"))).To(gomega.BeFalse())
+				g.Expect(evaluate(detect.FileGlob("**/*.csproj"))).To(gomega.BeTrue())
+			})
+		})
+
+		when("document keys", func() {
+
+			it("matches JSON key", func() {
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "package.json"),
+					`{ "scripts": { "start": "node server.js" } }`)
+
+				g.Expect(evaluate(detect.JSONKey("package.json", "scripts.start"))).To(gomega.BeTrue())
+				g.Expect(evaluate(detect.JSONKey("package.json", "scripts.build"))).To(gomega.BeFalse())
+			})
+
+			it("matches YAML key", func() {
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "buildpack.yml"), "go:\n  targets: [./cmd]\n")
+
+				g.Expect(evaluate(detect.YAMLKey("buildpack.yml", "go.targets"))).To(gomega.BeTrue())
+				g.Expect(evaluate(detect.YAMLKey("buildpack.yml", "go.version"))).To(gomega.BeFalse())
+			})
+
+			it("matches TOML key", func() {
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "Cargo.toml"), "[package]\nname = \"test\"\n")
+
+				g.Expect(evaluate(detect.TOMLKey("Cargo.toml", "package.name"))).To(gomega.BeTrue())
+				g.Expect(evaluate(detect.TOMLKey("Cargo.toml", "package.version"))).To(gomega.BeFalse())
+			})
+
+			it("does not match missing file", func() {
+				g.Expect(evaluate(detect.JSONKey("package.json", "scripts"))).To(gomega.BeFalse())
+			})
+
+			it("does not match invalid file", func() {
+				var debug bytes.Buffer
+				f.Detect.Logger = logger.Logger{Logger: loggerBp.NewLogger(&debug, nil)}
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "package.json"), "{")
+
+				g.Expect(evaluate(detect.JSONKey("package.json", "scripts"))).To(gomega.BeFalse())
+				g.Expect(debug.String()).To(gomega.ContainSubstring("Unable to parse package.json as JSON:"))
+			})
+		})
+
+		it("matches manifest attribute", func() {
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"), "Main-Class: test-class")
+
+			g.Expect(evaluate(detect.ManifestAttribute("Main-Class"))).To(gomega.BeTrue())
+			g.Expect(evaluate(detect.ManifestAttribute("Start-Class"))).To(gomega.BeFalse())
+		})
+
+		it("matches service", func() {
+			f.AddService("test-service", services.Credentials{"test-key": "test-value"})
+
+			g.Expect(evaluate(detect.Service("test-service", "test-key"))).To(gomega.BeTrue())
+			g.Expect(evaluate(detect.Service("other-service"))).To(gomega.BeFalse())
+		})
+
+		it("matches environment variable", func() {
+			defer test.ReplaceEnv(t, "TEST_KEY", "test-value")()
+
+			g.Expect(evaluate(detect.EnvironmentVariable("TEST_KEY"))).To(gomega.BeTrue())
+			g.Expect(evaluate(detect.EnvironmentVariable("TEST_OTHER_KEY"))).To(gomega.BeFalse())
+		})
+
+		when("composition", func() {
+
+			match := detect.NewRule("match", func(detect.Detect) (bool, error) { return true, nil })
+			miss := detect.NewRule("miss", func(detect.Detect) (bool, error) { return false, nil })
+			failure := detect.NewRule("failure", func(detect.Detect) (bool, error) { return false, fmt.Errorf("test-error") })
+
+			it("matches all", func() {
+				g.Expect(evaluate(detect.All(match, match))).To(gomega.BeTrue())
+				g.Expect(evaluate(detect.All(match, miss))).To(gomega.BeFalse())
+			})
+
+			it("matches any", func() {
+				g.Expect(evaluate(detect.Any(miss, match))).To(gomega.BeTrue())
+				g.Expect(evaluate(detect.Any(miss, miss))).To(gomega.BeFalse())
+			})
+
+			it("nests rules", func() {
+				r := detect.Any(miss, detect.All(match, match))
+
+				g.Expect(r.String()).To(gomega.Equal("(miss or (match and match))"))
+				g.Expect(evaluate(r)).To(gomega.BeTrue())
+			})
+
+			it("returns error", func() {
+				_, err := f.Detect.Evaluate(detect.Any(miss, failure))
+				g.Expect(err).To(gomega.MatchError("unable to evaluate failure: test-error"))
+			})
+
+			it("logs matching rules", func() {
+				var debug bytes.Buffer
+				f.Detect.Logger = logger.Logger{Logger: loggerBp.NewLogger(&debug, nil)}
+
+				evaluate(detect.Any(miss, match))
+
+				g.Expect(debug.String()).To(gomega.Equal("Matched match\nDetection rule matched: (miss or match)\n"))
+			})
+		})
+
+		when("PassIf", func() {
+
+			it("passes when rule matches", func() {
+				test.TouchFile(t, f.Detect.Application.Root, "test.csproj")
+
+				g.Expect(f.Detect.PassIf(detect.FileGlob("*.csproj"), buildplan.Plan{
+					Provides: []buildplan.Provided{{Name: "test-provided"}},
+				})).To(gomega.Equal(detect.PassStatusCode))
+				g.Expect(f.Plans.Provides).To(gomega.Equal([]buildplan.Provided{{Name: "test-provided"}}))
+			})
+
+			it("fails when rule does not match", func() {
+				g.Expect(f.Detect.PassIf(detect.FileGlob("*.csproj"))).To(gomega.Equal(detect.FailStatusCode))
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -16,18 +16,30 @@
 
 package internal
 
-// Set represents the mathematical type set.
+import (
+	"fmt"
+	"sync"
+)
+
+// Set represents the mathematical type set.  It is safe for concurrent use.
 type Set struct {
 	contents map[interface{}]struct{}
+	mutex    *sync.RWMutex
 }
 
 // Add adds an element to the set.
 func (s Set) Add(v interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.contents[v] = struct{}{}
 }
 
 // Contains returns whether the set contains an item.
 func (s Set) Contains(v interface{}) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
 	_, ok := s.contents[v]
 	return ok
 }
@@ -49,15 +61,16 @@ func (s Set) Difference(other Set) Set {
 // Iterator is a type that range-able.
 type Iterator <-chan interface{}
 
-// Iterator returns the values to be ranged over.
+// Iterator returns the values to be ranged over.  The values are a snapshot of the set at the time of the call.
 func (s Set) Iterator() Iterator {
+	values := s.values()
 	ch := make(chan interface{})
 
 	go func() {
 		defer close(ch)
 
-		for k, _ := range s.contents {
-			ch <- k
+		for _, v := range values {
+			ch <- v
 		}
 	}()
 
@@ -66,10 +79,30 @@ func (s Set) Iterator() Iterator {
 
 // Size returns the number of elements in the set.
 func (s Set) Size() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
 	return len(s.contents)
 }
 
+// String makes Set satisfy the Stringer interface.
+func (s Set) String() string {
+	return fmt.Sprint(s.values())
+}
+
+func (s Set) values() []interface{} {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	values := make([]interface{}, 0, len(s.contents))
+	for k := range s.contents {
+		values = append(values, k)
+	}
+
+	return values
+}
+
 // NewSet creates an initialized and empty Set.
 func NewSet() Set {
-	return Set{make(map[interface{}]struct{})}
+	return Set{make(map[interface{}]struct{}), &sync.RWMutex{}}
 }
@@ -0,0 +1,247 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/libbuildpack/v2/application"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/manifest"
+)
+
+// ApplicationJARDirectories are the directories, relative to the application root, that contain the library JARs of
+// Spring Boot fat JARs and exploded WARs.
+var ApplicationJARDirectories = []string{"BOOT-INF/lib", "WEB-INF/lib"}
+
+var jarFileName = regexp.MustCompile(`^(.+?)-([0-9].*)\.jar$`)
+
+// ApplicationDependency is a package that is part of the application itself, rather than contributed by a buildpack.
+type ApplicationDependency struct {
+	// Name is the name of the package.
+	Name string
+
+	// Version is the version of the package.
+	Version string
+
+	// SHA256 is the hash of the package.
+	SHA256 string
+
+	// Licenses are the licenses the package is distributed under.
+	Licenses buildpack.Licenses
+
+	// PURL is the package URL of the package.  If empty, a generic package URL is used in the bill-of-materials.
+	PURL string
+
+	// Path is the location of the package, relative to the application root.
+	Path string
+}
+
+// AddApplicationDependencies adds packages discovered in the application to the launch bill-of-materials.
+func (l Layers) AddApplicationDependencies(dependencies ...ApplicationDependency) {
+	var entries []buildpackplan.Plan
+
+	for _, d := range dependencies {
+		e := buildpackplan.Plan{Name: d.Name, Version: d.Version, Metadata: buildpackplan.Metadata{}}
+
+		if d.SHA256 != "" {
+			e.Metadata["sha256"] = d.SHA256
+		}
+
+		if len(d.Licenses) > 0 {
+			e.Metadata["licenses"] = d.Licenses
+		}
+
+		if d.PURL != "" {
+			e.Metadata["purl"] = d.PURL
+		}
+
+		if d.Path != "" {
+			e.Metadata["path"] = d.Path
+		}
+
+		entries = append(entries, e)
+	}
+
+	appendPlans(l.plansMutex, l.Plans, entries...)
+}
+
+// JARDependencies scans the JARs in the ApplicationJARDirectories of an application and describes each of them using
+// the contents of its META-INF/MANIFEST.MF.  When the manifest does not declare a name or version, they are derived
+// from the JAR's file name.
+func JARDependencies(application application.Application, logger logger.Logger) ([]ApplicationDependency, error) {
+	var dependencies []ApplicationDependency
+
+	for _, d := range ApplicationJARDirectories {
+		files, err := filepath.Glob(filepath.Join(application.Root, filepath.FromSlash(d), "*.jar"))
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Strings(files)
+
+		for _, f := range files {
+			dependency, err := jarDependency(application.Root, f, logger)
+			if err != nil {
+				return nil, fmt.Errorf("unable to describe %s: %s", f, err.Error())
+			}
+
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	return dependencies, nil
+}
+
+func jarDependency(root string, file string, logger logger.Logger) (ApplicationDependency, error) {
+	m, err := manifest.NewManifestFromJAR(file, logger)
+	if err != nil {
+		return ApplicationDependency{}, err
+	}
+
+	rel, err := filepath.Rel(root, file)
+	if err != nil {
+		return ApplicationDependency{}, err
+	}
+
+	d := ApplicationDependency{
+		Name:    firstValue(m, "Implementation-Title", "Bundle-SymbolicName"),
+		Version: firstValue(m, "Implementation-Version", "Bundle-Version"),
+		Path:    filepath.ToSlash(rel),
+	}
+
+	artifact := strings.TrimSuffix(filepath.Base(file), ".jar")
+	if g := jarFileName.FindStringSubmatch(filepath.Base(file)); g != nil {
+		artifact = g[1]
+		if d.Version == "" {
+			d.Version = g[2]
+		}
+	}
+
+	if d.Name == "" {
+		d.Name = artifact
+	}
+
+	if l, ok := m.Get("Bundle-License"); ok {
+		d.Licenses = bundleLicenses(l)
+	}
+
+	if g, ok := m.Get("Implementation-Vendor-Id"); ok && g != "" && d.Version != "" {
+		d.PURL = fmt.Sprintf("pkg:maven/%s/%s@%s", url.PathEscape(g), url.PathEscape(artifact), url.PathEscape(d.Version))
+	}
+
+	if d.SHA256, err = sha256File(file); err != nil {
+		return ApplicationDependency{}, err
+	}
+
+	return d, nil
+}
+
+// bundleLicenses parses an OSGi Bundle-License header.  The header is a comma-separated list of licenses, each of which
+// is an SPDX identifier or a URI, optionally followed by ;-separated parameters.  The link parameter is the URI of the
+// license.
+func bundleLicenses(header string) buildpack.Licenses {
+	var licenses buildpack.Licenses
+
+	for _, clause := range splitUnquoted(header, ',') {
+		parts := splitUnquoted(clause, ';')
+
+		name := unquote(parts[0])
+		if name == "" {
+			continue
+		}
+
+		var l buildpack.License
+		if strings.Contains(name, "://") {
+			l.URI = name
+		} else {
+			l.Type = name
+		}
+
+		for _, p := range parts[1:] {
+			kv := strings.SplitN(p, "=", 2)
+			if len(kv) == 2 && strings.TrimSpace(kv[0]) == "link" {
+				l.URI = unquote(kv[1])
+			}
+		}
+
+		licenses = append(licenses, l)
+	}
+
+	return licenses
+}
+
+func splitUnquoted(s string, separator rune) []string {
+	var (
+		parts  []string
+		quoted bool
+		start  int
+	)
+
+	for i, c := range s {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == separator && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+func unquote(s string) string {
+	return strings.Trim(strings.TrimSpace(s), `"`)
+}
+
+func firstValue(m manifest.Manifest, keys ...string) string {
+	for _, k := range keys {
+		if v, ok := m.Get(k); ok && v != "" {
+			// OSGi symbolic names may carry directives such as ";singleton:=true"
+			return strings.TrimSpace(strings.SplitN(v, ";", 2)[0])
+		}
+	}
+
+	return ""
+}
+
+func sha256File(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	s := sha256.New()
+	if _, err := io.Copy(s, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(s.Sum(nil)), nil
+}
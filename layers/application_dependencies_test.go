@@ -0,0 +1,157 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestApplicationDependencies(t *testing.T) {
+	spec.Run(t, "ApplicationDependencies", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		sha256Of := func(file string) string {
+			b, err := ioutil.ReadFile(file)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			s := sha256.Sum256(b)
+			return hex.EncodeToString(s[:])
+		}
+
+		it("adds application dependencies to the bill-of-materials", func() {
+			f.Build.Layers.AddApplicationDependencies(
+				layers.ApplicationDependency{
+					Name:     "test-name",
+					Version:  "1.0",
+					SHA256:   "test-sha256",
+					Licenses: buildpack.Licenses{{Type: "Apache-2.0"}},
+					PURL:     "pkg:maven/test-group/test-name@1.0",
+					Path:     "BOOT-INF/lib/test-name-1.0.jar",
+				},
+				layers.ApplicationDependency{Name: "test-other", Version: "2.0"},
+			)
+
+			g.Expect(f.Build.Layers.Plans.Entries).To(gomega.Equal([]buildpackplan.Plan{
+				{
+					Name:    "test-name",
+					Version: "1.0",
+					Metadata: buildpackplan.Metadata{
+						"sha256":   "test-sha256",
+						"licenses": buildpack.Licenses{{Type: "Apache-2.0"}},
+						"purl":     "pkg:maven/test-group/test-name@1.0",
+						"path":     "BOOT-INF/lib/test-name-1.0.jar",
+					},
+				},
+				{Name: "test-other", Version: "2.0", Metadata: buildpackplan.Metadata{}},
+			}))
+		})
+
+		it("uses declared package URL in bill-of-materials", func() {
+			f.Build.Layers.AddApplicationDependencies(layers.ApplicationDependency{
+				Name:    "test-name",
+				Version: "1.0",
+				PURL:    "pkg:maven/test-group/test-name@1.0",
+			})
+
+			g.Expect(f.Build.Layers.WriteBillOfMaterials()).To(gomega.Succeed())
+
+			b, err := ioutil.ReadFile(filepath.Join(f.Build.Layers.Root, layers.CycloneDXBillOfMaterials))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(string(b)).To(gomega.ContainSubstring(`"purl": "pkg:maven/test-group/test-name@1.0"`))
+		})
+
+		it("returns no dependencies without library directories", func() {
+			g.Expect(layers.JARDependencies(f.Build.Application, f.Build.Logger)).To(gomega.BeEmpty())
+		})
+
+		it("describes JARs from their manifests", func() {
+			boot := filepath.Join(f.Build.Application.Root, "BOOT-INF", "lib", "test-boot-1.2.3.jar")
+			test.WriteJAR(t, boot, map[string]string{"META-INF/MANIFEST.MF": `Implementation-Title: Test Boot
+Implementation-Version: 1.2.3.RELEASE
+Implementation-Vendor-Id: org.test
+Bundle-License: Apache-2.0
+`})
+
+			web := filepath.Join(f.Build.Application.Root, "WEB-INF", "lib", "test-web.jar")
+			test.WriteJAR(t, web, map[string]string{"META-INF/MANIFEST.MF": `Bundle-SymbolicName: org.test.web;singleton:=true
+Bundle-Version: 4.5.6
+Bundle-License: https://test.com/license
+`})
+
+			g.Expect(layers.JARDependencies(f.Build.Application, f.Build.Logger)).To(gomega.Equal([]layers.ApplicationDependency{
+				{
+					Name:     "Test Boot",
+					Version:  "1.2.3.RELEASE",
+					SHA256:   sha256Of(boot),
+					Licenses: buildpack.Licenses{{Type: "Apache-2.0"}},
+					PURL:     "pkg:maven/org.test/test-boot@1.2.3.RELEASE",
+					Path:     "BOOT-INF/lib/test-boot-1.2.3.jar",
+				},
+				{
+					Name:     "org.test.web",
+					Version:  "4.5.6",
+					SHA256:   sha256Of(web),
+					Licenses: buildpack.Licenses{{URI: "https://test.com/license"}},
+					Path:     "WEB-INF/lib/test-web.jar",
+				},
+			}))
+		})
+
+		it("describes licenses with parameters and lists", func() {
+			jar := filepath.Join(f.Build.Application.Root, "BOOT-INF", "lib", "test-name-1.0.0.jar")
+			test.WriteJAR(t, jar, map[string]string{"META-INF/MANIFEST.MF": `Bundle-License: Apache-2.0;link="https://www.apache.org/licenses/LICENSE-2.0.txt";description="Apache License, Version 2.0", "https://test.com/license", MIT
+`})
+
+			d, err := layers.JARDependencies(f.Build.Application, f.Build.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(d).To(gomega.HaveLen(1))
+			g.Expect(d[0].Licenses).To(gomega.Equal(buildpack.Licenses{
+				{Type: "Apache-2.0", URI: "https://www.apache.org/licenses/LICENSE-2.0.txt"},
+				{URI: "https://test.com/license"},
+				{Type: "MIT"},
+			}))
+		})
+
+		it("derives name and version from file name", func() {
+			jar := filepath.Join(f.Build.Application.Root, "BOOT-INF", "lib", "test-name-2.0.1.jar")
+			test.WriteJAR(t, jar, map[string]string{})
+
+			g.Expect(layers.JARDependencies(f.Build.Application, f.Build.Logger)).To(gomega.Equal([]layers.ApplicationDependency{
+				{Name: "test-name", Version: "2.0.1", SHA256: sha256Of(jar), Path: "BOOT-INF/lib/test-name-2.0.1.jar"},
+			}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
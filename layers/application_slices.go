@@ -0,0 +1,179 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/libbuildpack/v2/application"
+)
+
+// SlicePattern describes the contents of an application slice.  Patterns are relative to the application root and use
+// '/' as a separator.  In addition to the syntax of filepath.Match, a '**' path segment matches zero or more
+// directories.  A pattern that matches a directory includes every file beneath it.
+type SlicePattern struct {
+	// Include are the patterns of files to include in the slice.
+	Include []string
+
+	// Exclude are the patterns of files to exclude from the slice, even if they match an Include pattern.
+	Exclude []string
+}
+
+// ApplicationSlices resolves a collection of SlicePatterns against the files in an application and returns the Slices
+// suitable for use with WriteApplicationMetadata.  Each file in the application may belong to at most one slice and an
+// error is returned if patterns overlap.  Slices that match no files are omitted.
+func ApplicationSlices(application application.Application, patterns ...SlicePattern) (Slices, error) {
+	files, err := applicationFiles(application.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make(map[string][]int)
+	contents := make([][]string, len(patterns))
+
+	for i, p := range patterns {
+		for _, f := range files {
+			in, err := p.matches(f)
+			if err != nil {
+				return nil, err
+			}
+
+			if in {
+				owners[f] = append(owners[f], i)
+				contents[i] = append(contents[i], f)
+			}
+		}
+	}
+
+	var overlaps []string
+	for _, f := range files {
+		if o := owners[f]; len(o) > 1 {
+			overlaps = append(overlaps, fmt.Sprintf("%s (slices %s)", f, joinInts(o)))
+		}
+	}
+
+	if len(overlaps) > 0 {
+		return nil, fmt.Errorf("files belong to more than one slice:\n%s", strings.Join(overlaps, "\n"))
+	}
+
+	var slices Slices
+	for _, c := range contents {
+		if len(c) > 0 {
+			slices = append(slices, Slice{Paths: c})
+		}
+	}
+
+	return slices, nil
+}
+
+func (s SlicePattern) matches(file string) (bool, error) {
+	included, err := matchesAny(s.Include, file)
+	if err != nil || !included {
+		return false, err
+	}
+
+	excluded, err := matchesAny(s.Exclude, file)
+	if err != nil {
+		return false, err
+	}
+
+	return !excluded, nil
+}
+
+func applicationFiles(root string) ([]string, error) {
+	var files []string
+
+	if err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func joinInts(values []int) string {
+	var s []string
+
+	for _, v := range values {
+		s = append(s, fmt.Sprintf("%d", v))
+	}
+
+	return strings.Join(s, ", ")
+}
+
+func matchesAny(patterns []string, file string) (bool, error) {
+	for _, p := range patterns {
+		m, err := matchSegments(strings.Split(strings.Trim(p, "/"), "/"), strings.Split(file, "/"))
+		if err != nil {
+			return false, fmt.Errorf("invalid slice pattern %s: %s", p, err.Error())
+		}
+
+		if m {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func matchSegments(pattern []string, path []string) (bool, error) {
+	if len(pattern) == 0 {
+		// a pattern that matches a parent directory includes everything beneath it
+		return true, nil
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if m, err := matchSegments(pattern[1:], path[i:]); err != nil || m {
+				return m, err
+			}
+		}
+
+		return false, nil
+	}
+
+	if len(path) == 0 {
+		return false, nil
+	}
+
+	m, err := filepath.Match(pattern[0], path[0])
+	if err != nil || !m {
+		return false, err
+	}
+
+	return matchSegments(pattern[1:], path[1:])
+}
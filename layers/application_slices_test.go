@@ -0,0 +1,89 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers_test
+
+import (
+	"testing"
+
+	"github.com/buildpacks/libbuildpack/v2/application"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestApplicationSlices(t *testing.T) {
+	spec.Run(t, "ApplicationSlices", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var app application.Application
+
+		it.Before(func() {
+			app = application.Application{Root: test.ScratchDir(t, "application-slices")}
+
+			test.TouchFile(t, app.Root, "BOOT-INF", "lib", "alpha-1.0.0.jar")
+			test.TouchFile(t, app.Root, "BOOT-INF", "lib", "bravo-1.0.0-SNAPSHOT.jar")
+			test.TouchFile(t, app.Root, "BOOT-INF", "classes", "application.properties")
+			test.TouchFile(t, app.Root, "BOOT-INF", "classes", "com", "example", "Application.class")
+			test.TouchFile(t, app.Root, "META-INF", "MANIFEST.MF")
+		})
+
+		it("resolves patterns", func() {
+			g.Expect(layers.ApplicationSlices(app,
+				layers.SlicePattern{Include: []string{"BOOT-INF/lib/*.jar"}, Exclude: []string{"**/*-SNAPSHOT.jar"}},
+				layers.SlicePattern{Include: []string{"BOOT-INF/lib/*-SNAPSHOT.jar"}},
+				layers.SlicePattern{Include: []string{"BOOT-INF/classes"}},
+			)).To(gomega.Equal(layers.Slices{
+				{Paths: []string{"BOOT-INF/lib/alpha-1.0.0.jar"}},
+				{Paths: []string{"BOOT-INF/lib/bravo-1.0.0-SNAPSHOT.jar"}},
+				{Paths: []string{"BOOT-INF/classes/application.properties", "BOOT-INF/classes/com/example/Application.class"}},
+			}))
+		})
+
+		it("matches any directory depth with **", func() {
+			g.Expect(layers.ApplicationSlices(app,
+				layers.SlicePattern{Include: []string{"**/*.class"}},
+			)).To(gomega.Equal(layers.Slices{
+				{Paths: []string{"BOOT-INF/classes/com/example/Application.class"}},
+			}))
+		})
+
+		it("omits empty slices", func() {
+			g.Expect(layers.ApplicationSlices(app,
+				layers.SlicePattern{Include: []string{"WEB-INF/lib/*.jar"}},
+			)).To(gomega.BeEmpty())
+		})
+
+		it("rejects files in more than one slice", func() {
+			_, err := layers.ApplicationSlices(app,
+				layers.SlicePattern{Include: []string{"BOOT-INF/lib"}},
+				layers.SlicePattern{Include: []string{"**/*-SNAPSHOT.jar"}},
+			)
+
+			g.Expect(err).To(gomega.MatchError(`files belong to more than one slice:
+BOOT-INF/lib/bravo-1.0.0-SNAPSHOT.jar (slices 0, 1)`))
+		})
+
+		it("rejects invalid patterns", func() {
+			_, err := layers.ApplicationSlices(app, layers.SlicePattern{Include: []string{"BOOT-INF/["}})
+
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+	}, spec.Report(report.Terminal{}))
+}
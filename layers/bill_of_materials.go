@@ -0,0 +1,454 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+)
+
+const (
+	// BuildCycloneDXBillOfMaterials is the name of the file, in the layers root, that the build bill-of-materials is
+	// written to as a CycloneDX JSON document.
+	BuildCycloneDXBillOfMaterials = "build.sbom.cdx.json"
+
+	// BuildSPDXBillOfMaterials is the name of the file, in the layers root, that the build bill-of-materials is written
+	// to as an SPDX JSON document.
+	BuildSPDXBillOfMaterials = "build.sbom.spdx.json"
+
+	// CycloneDXBillOfMaterials is the name of the file, in the layers root, that the launch bill-of-materials is written
+	// to as a CycloneDX JSON document.
+	CycloneDXBillOfMaterials = "launch.sbom.cdx.json"
+
+	// SPDXBillOfMaterials is the name of the file, in the layers root, that the launch bill-of-materials is written to as
+	// an SPDX JSON document.
+	SPDXBillOfMaterials = "launch.sbom.spdx.json"
+)
+
+var spdxIDInvalid = regexp.MustCompile(`[^A-Za-z0-9.-]`)
+
+// WriteBillOfMaterials writes the launch bill-of-materials entries in Plans and the build bill-of-materials entries in
+// BuildPlans to the layers root as both CycloneDX and SPDX JSON documents.  If there are no entries for either, those
+// documents are not written.
+func (l Layers) WriteBillOfMaterials() error {
+	if err := l.writeBillOfMaterials(l.Plans.Entries, CycloneDXBillOfMaterials, SPDXBillOfMaterials); err != nil {
+		return err
+	}
+
+	return l.writeBillOfMaterials(l.BuildPlans.Entries, BuildCycloneDXBillOfMaterials, BuildSPDXBillOfMaterials)
+}
+
+func (l Layers) writeBillOfMaterials(entries []buildpackplan.Plan, cycloneDXFile string, spdxFile string) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	created, err := creationTime()
+	if err != nil {
+		return err
+	}
+
+	var packages []bomPackage
+	for _, e := range entries {
+		packages = append(packages, newBOMPackage(e))
+	}
+
+	sort.Slice(packages, func(i int, j int) bool {
+		if packages[i].id == packages[j].id {
+			return packages[i].version < packages[j].version
+		}
+
+		return packages[i].id < packages[j].id
+	})
+
+	l.logger.Body("Writing %s", cycloneDXFile)
+	l.logger.Body("Writing %s", spdxFile)
+
+	if err := writeJSON(filepath.Join(l.Root, cycloneDXFile), newCycloneDX(l.buildpack.Info, created, packages)); err != nil {
+		return err
+	}
+
+	return writeJSON(filepath.Join(l.Root, spdxFile), newSPDX(l.buildpack.Info, created, packages))
+}
+
+type bomPackage struct {
+	id          string
+	version     string
+	description string
+	uri         string
+	sha256      string
+	packageURL  string
+	os          string
+	arch        string
+	stacks      []string
+	licenses    buildpack.Licenses
+}
+
+func newBOMPackage(plan buildpackplan.Plan) bomPackage {
+	p := bomPackage{id: plan.Name, version: plan.Version}
+
+	p.description, _ = plan.Metadata["name"].(string)
+	p.uri, _ = plan.Metadata["uri"].(string)
+	p.sha256, _ = plan.Metadata["sha256"].(string)
+	p.packageURL, _ = plan.Metadata["purl"].(string)
+	p.os, _ = plan.Metadata["os"].(string)
+	p.arch, _ = plan.Metadata["arch"].(string)
+
+	switch s := plan.Metadata["stacks"].(type) {
+	case buildpack.Stacks:
+		for _, v := range s {
+			p.stacks = append(p.stacks, string(v))
+		}
+	case []string:
+		p.stacks = s
+	case []interface{}:
+		for _, v := range s {
+			p.stacks = append(p.stacks, fmt.Sprintf("%s", v))
+		}
+	}
+
+	switch l := plan.Metadata["licenses"].(type) {
+	case buildpack.Licenses:
+		p.licenses = l
+	case []buildpack.License:
+		p.licenses = l
+	case []interface{}:
+		for _, v := range l {
+			if m, ok := v.(map[string]interface{}); ok {
+				t, _ := m["type"].(string)
+				u, _ := m["uri"].(string)
+				p.licenses = append(p.licenses, buildpack.License{Type: t, URI: u})
+			}
+		}
+	}
+
+	return p
+}
+
+// licenseExpression returns an SPDX license expression requiring all of the declared licenses, or an empty string if
+// no license has an SPDX type.
+func (p bomPackage) licenseExpression() string {
+	var types []string
+
+	for _, l := range p.licenses {
+		if l.Type != "" {
+			types = append(types, l.Type)
+		}
+	}
+
+	if len(types) > 1 {
+		for i, t := range types {
+			if strings.Contains(t, " ") {
+				types[i] = fmt.Sprintf("(%s)", t)
+			}
+		}
+	}
+
+	return strings.Join(types, " AND ")
+}
+
+// purl returns the declared package URL of the package or, if none is declared, one using the generic package type.
+func (p bomPackage) purl() string {
+	if p.packageURL != "" {
+		return p.packageURL
+	}
+
+	var q []string
+
+	if p.arch != "" {
+		q = append(q, fmt.Sprintf("arch=%s", url.QueryEscape(p.arch)))
+	}
+
+	if p.os != "" {
+		q = append(q, fmt.Sprintf("os=%s", url.QueryEscape(p.os)))
+	}
+
+	if p.uri != "" {
+		q = append(q, fmt.Sprintf("download_url=%s", url.QueryEscape(p.uri)))
+	}
+
+	if p.sha256 != "" {
+		q = append(q, fmt.Sprintf("checksum=sha256:%s", p.sha256))
+	}
+
+	s := fmt.Sprintf("pkg:generic/%s", url.PathEscape(p.id))
+	if p.version != "" {
+		s = fmt.Sprintf("%s@%s", s, url.PathEscape(p.version))
+	}
+
+	if len(q) > 0 {
+		s = fmt.Sprintf("%s?%s", s, strings.Join(q, "&"))
+	}
+
+	return s
+}
+
+type cycloneDX struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Metadata    cycloneDXMetadata    `json:"metadata"`
+	Components  []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string          `json:"timestamp"`
+	Tools     []cycloneDXTool `json:"tools"`
+}
+
+type cycloneDXTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef             string                       `json:"bom-ref"`
+	Type               string                       `json:"type"`
+	Name               string                       `json:"name"`
+	Version            string                       `json:"version,omitempty"`
+	Description        string                       `json:"description,omitempty"`
+	Hashes             []cycloneDXHash              `json:"hashes,omitempty"`
+	Licenses           []cycloneDXLicenseChoice     `json:"licenses,omitempty"`
+	PURL               string                       `json:"purl"`
+	ExternalReferences []cycloneDXExternalReference `json:"externalReferences,omitempty"`
+	Properties         []cycloneDXProperty          `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type cycloneDXLicenseChoice struct {
+	License cycloneDXLicense `json:"license"`
+}
+
+type cycloneDXLicense struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type cycloneDXExternalReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func newCycloneDX(info buildpack.Info, created time.Time, packages []bomPackage) cycloneDX {
+	c := cycloneDX{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.3",
+		Version:     1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: created.Format(time.RFC3339),
+			Tools:     []cycloneDXTool{{Name: info.ID, Version: info.Version}},
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	for _, p := range packages {
+		component := cycloneDXComponent{
+			BOMRef:      p.purl(),
+			Type:        "library",
+			Name:        p.id,
+			Version:     p.version,
+			Description: p.description,
+			PURL:        p.purl(),
+		}
+
+		if p.sha256 != "" {
+			component.Hashes = append(component.Hashes, cycloneDXHash{Algorithm: "SHA-256", Content: p.sha256})
+		}
+
+		for _, l := range p.licenses {
+			if l.Type != "" {
+				component.Licenses = append(component.Licenses, cycloneDXLicenseChoice{cycloneDXLicense{ID: l.Type, URL: l.URI}})
+			} else {
+				component.Licenses = append(component.Licenses, cycloneDXLicenseChoice{cycloneDXLicense{Name: l.URI, URL: l.URI}})
+			}
+		}
+
+		if p.uri != "" {
+			component.ExternalReferences = append(component.ExternalReferences, cycloneDXExternalReference{Type: "distribution", URL: p.uri})
+		}
+
+		if len(p.stacks) > 0 {
+			component.Properties = append(component.Properties, cycloneDXProperty{Name: "cnb:stacks", Value: strings.Join(p.stacks, ",")})
+		}
+
+		if p.os != "" {
+			component.Properties = append(component.Properties, cycloneDXProperty{Name: "cnb:os", Value: p.os})
+		}
+
+		if p.arch != "" {
+			component.Properties = append(component.Properties, cycloneDXProperty{Name: "cnb:arch", Value: p.arch})
+		}
+
+		c.Components = append(c.Components, component)
+	}
+
+	return c
+}
+
+type spdx struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+func newSPDX(info buildpack.Info, created time.Time, packages []bomPackage) spdx {
+	s := spdx{
+		SPDXVersion: "SPDX-2.2",
+		DataLicense: "CC0-1.0",
+		SPDXID:      "SPDXRef-DOCUMENT",
+		Name:        info.ID,
+		CreationInfo: spdxCreationInfo{
+			Created:  created.Format(time.RFC3339),
+			Creators: []string{fmt.Sprintf("Tool: %s-%s", info.ID, info.Version)},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	h := sha256.New()
+	for i, p := range packages {
+		id := fmt.Sprintf("SPDXRef-Package-%s-%d", spdxIDInvalid.ReplaceAllString(p.id, "-"), i)
+
+		pkg := spdxPackage{
+			SPDXID:           id,
+			Name:             p.id,
+			VersionInfo:      p.version,
+			Description:      p.description,
+			DownloadLocation: "NOASSERTION",
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  "NOASSERTION",
+			CopyrightText:    "NOASSERTION",
+			ExternalRefs: []spdxExternalRef{
+				{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: p.purl()},
+			},
+		}
+
+		if p.uri != "" {
+			pkg.DownloadLocation = p.uri
+		}
+
+		if p.sha256 != "" {
+			pkg.Checksums = append(pkg.Checksums, spdxChecksum{Algorithm: "SHA256", ChecksumValue: p.sha256})
+		}
+
+		if e := p.licenseExpression(); e != "" {
+			pkg.LicenseDeclared = e
+		}
+
+		s.Packages = append(s.Packages, pkg)
+		s.Relationships = append(s.Relationships, spdxRelationship{
+			SPDXElementID:      "SPDXRef-DOCUMENT",
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: id,
+		})
+
+		_, _ = h.Write([]byte(p.purl()))
+	}
+
+	s.DocumentNamespace = fmt.Sprintf("https://spdx.org/spdxdocs/%s-%s", url.PathEscape(info.ID), hex.EncodeToString(h.Sum(nil)))
+
+	return s
+}
+
+// creationTime returns the time used for bill-of-materials creation.  SOURCE_DATE_EPOCH is used if set in order to
+// keep bill-of-materials documents reproducible.
+func creationTime() (time.Time, error) {
+	if _, ok := os.LookupEnv(SourceDateEpoch); ok {
+		return modificationTime()
+	}
+
+	return time.Now().UTC(), nil
+}
+
+func writeJSON(file string, value interface{}) error {
+	var b bytes.Buffer
+
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+
+	if err := e.Encode(value); err != nil {
+		return err
+	}
+
+	return helper.WriteFileFromReader(file, 0644, &b)
+}
@@ -0,0 +1,184 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	layersBp "github.com/buildpacks/libbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestBillOfMaterials(t *testing.T) {
+	spec.Run(t, "BillOfMaterials", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			root string
+			ls   layers.Layers
+		)
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "bill-of-materials")
+
+			b := buildpack.Buildpack{}
+			b.Info.ID = "test-buildpack-id"
+			b.Info.Version = "1.0"
+
+			ls = layers.NewLayers(layersBp.Layers{Root: root}, layersBp.Layers{}, b, logger.Logger{})
+		})
+
+		read := func(file string) []byte {
+			b, err := ioutil.ReadFile(filepath.Join(root, file))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			return b
+		}
+
+		it("does not write documents without entries", func() {
+			g.Expect(ls.WriteBillOfMaterials()).To(gomega.Succeed())
+
+			g.Expect(filepath.Join(root, layers.CycloneDXBillOfMaterials)).NotTo(gomega.BeAnExistingFile())
+			g.Expect(filepath.Join(root, layers.SPDXBillOfMaterials)).NotTo(gomega.BeAnExistingFile())
+		})
+
+		it("writes build documents separately", func() {
+			ls.BuildPlans.Entries = append(ls.BuildPlans.Entries, buildpackplan.Plan{Name: "test-build-id", Version: "1.0"})
+
+			g.Expect(ls.WriteBillOfMaterials()).To(gomega.Succeed())
+
+			g.Expect(filepath.Join(root, layers.CycloneDXBillOfMaterials)).NotTo(gomega.BeAnExistingFile())
+			g.Expect(filepath.Join(root, layers.SPDXBillOfMaterials)).NotTo(gomega.BeAnExistingFile())
+			g.Expect(read(layers.BuildCycloneDXBillOfMaterials)).To(gomega.ContainSubstring(`"purl": "pkg:generic/test-build-id@1.0"`))
+			g.Expect(read(layers.BuildSPDXBillOfMaterials)).To(gomega.ContainSubstring(`"referenceLocator": "pkg:generic/test-build-id@1.0"`))
+		})
+
+		it("includes platform in documents", func() {
+			ls.Plans.Entries = append(ls.Plans.Entries, buildpackplan.Plan{
+				Name:     "test-id",
+				Version:  "1.0",
+				Metadata: buildpackplan.Metadata{"os": "linux", "arch": "arm64"},
+			})
+
+			g.Expect(ls.WriteBillOfMaterials()).To(gomega.Succeed())
+
+			g.Expect(read(layers.CycloneDXBillOfMaterials)).To(gomega.ContainSubstring(`"purl": "pkg:generic/test-id@1.0?arch=arm64&os=linux"`))
+			g.Expect(read(layers.CycloneDXBillOfMaterials)).To(gomega.ContainSubstring(`"name": "cnb:arch"`))
+			g.Expect(read(layers.CycloneDXBillOfMaterials)).To(gomega.ContainSubstring(`"name": "cnb:os"`))
+		})
+
+		it("writes documents", func() {
+			defer test.ReplaceEnv(t, layers.SourceDateEpoch, "1234567890")()
+
+			ls.Plans.Entries = append(ls.Plans.Entries,
+				buildpackplan.Plan{
+					Name:    "test-id",
+					Version: "1.0",
+					Metadata: buildpackplan.Metadata{
+						"name":     "Test Name",
+						"uri":      "https://test.com/test-path",
+						"sha256":   "test-sha256",
+						"stacks":   buildpack.Stacks{"test-stack-1", "test-stack-2"},
+						"licenses": buildpack.Licenses{{Type: "Apache-2.0"}, {Type: "MIT", URI: "https://test.com/license"}},
+					},
+				},
+				buildpackplan.Plan{
+					Name:     "test-helper",
+					Version:  "1.0",
+					Metadata: buildpackplan.Metadata{"id": "test-buildpack-id", "name": "Test Helper"},
+				},
+			)
+
+			g.Expect(ls.WriteBillOfMaterials()).To(gomega.Succeed())
+
+			g.Expect(read(layers.CycloneDXBillOfMaterials)).To(gomega.MatchJSON(`{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.3",
+  "version": 1,
+  "metadata": {
+    "timestamp": "2009-02-13T23:31:30Z",
+    "tools": [ { "name": "test-buildpack-id", "version": "1.0" } ]
+  },
+  "components": [
+    {
+      "bom-ref": "pkg:generic/test-helper@1.0",
+      "type": "library",
+      "name": "test-helper",
+      "version": "1.0",
+      "description": "Test Helper",
+      "purl": "pkg:generic/test-helper@1.0"
+    },
+    {
+      "bom-ref": "pkg:generic/test-id@1.0?download_url=https%3A%2F%2Ftest.com%2Ftest-path&checksum=sha256:test-sha256",
+      "type": "library",
+      "name": "test-id",
+      "version": "1.0",
+      "description": "Test Name",
+      "hashes": [ { "alg": "SHA-256", "content": "test-sha256" } ],
+      "licenses": [
+        { "license": { "id": "Apache-2.0" } },
+        { "license": { "id": "MIT", "url": "https://test.com/license" } }
+      ],
+      "purl": "pkg:generic/test-id@1.0?download_url=https%3A%2F%2Ftest.com%2Ftest-path&checksum=sha256:test-sha256",
+      "externalReferences": [ { "type": "distribution", "url": "https://test.com/test-path" } ],
+      "properties": [ { "name": "cnb:stacks", "value": "test-stack-1,test-stack-2" } ]
+    }
+  ]
+}`))
+
+			spdx := read(layers.SPDXBillOfMaterials)
+			g.Expect(spdx).To(gomega.ContainSubstring(`"spdxVersion": "SPDX-2.2"`))
+			g.Expect(spdx).To(gomega.ContainSubstring(`"created": "2009-02-13T23:31:30Z"`))
+			g.Expect(spdx).To(gomega.ContainSubstring(`"documentNamespace": "https://spdx.org/spdxdocs/test-buildpack-id-`))
+			g.Expect(spdx).To(gomega.ContainSubstring(`{
+      "SPDXID": "SPDXRef-Package-test-id-1",
+      "name": "test-id",
+      "versionInfo": "1.0",
+      "description": "Test Name",
+      "downloadLocation": "https://test.com/test-path",
+      "filesAnalyzed": false,
+      "checksums": [
+        {
+          "algorithm": "SHA256",
+          "checksumValue": "test-sha256"
+        }
+      ],
+      "licenseConcluded": "NOASSERTION",
+      "licenseDeclared": "Apache-2.0 AND MIT",
+      "copyrightText": "NOASSERTION",
+      "externalRefs": [
+        {
+          "referenceCategory": "PACKAGE-MANAGER",
+          "referenceType": "purl",
+          "referenceLocator": "pkg:generic/test-id@1.0?download_url=https%3A%2F%2Ftest.com%2Ftest-path&checksum=sha256:test-sha256"
+        }
+      ]
+    }`))
+			g.Expect(spdx).To(gomega.ContainSubstring(`"downloadLocation": "NOASSERTION"`))
+			g.Expect(spdx).To(gomega.ContainSubstring(`"relatedSpdxElement": "SPDXRef-Package-test-helper-0"`))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers
+
+import (
+	"strings"
+	"sync"
+)
+
+// ContributionErrors is the collection of errors returned by concurrent contributions.
+type ContributionErrors []error
+
+// Error makes ContributionErrors satisfy the error interface.
+func (c ContributionErrors) Error() string {
+	var s []string
+
+	for _, e := range c {
+		s = append(s, e.Error())
+	}
+
+	return strings.Join(s, "\n")
+}
+
+// ContributeConcurrently calls each contributor in its own goroutine and waits for all of them to complete.  It is
+// intended for contributing several independent layers, for example:
+//
+//	err := layers.ContributeConcurrently(
+//	    func() error { return jre.Contribute(contributeJRE, layers.Launch) },
+//	    func() error { return agent.Contribute(contributeAgent, layers.Launch) },
+//	)
+//
+// If any contributors fail, all of their errors are returned, in contributor order, as ContributionErrors.
+func ContributeConcurrently(contributors ...func() error) error {
+	errors := make([]error, len(contributors))
+
+	var wg sync.WaitGroup
+	for i, c := range contributors {
+		wg.Add(1)
+
+		go func(i int, c func() error) {
+			defer wg.Done()
+			errors[i] = c()
+		}(i, c)
+	}
+
+	wg.Wait()
+
+	var e ContributionErrors
+	for _, err := range errors {
+		if err != nil {
+			e = append(e, err)
+		}
+	}
+
+	if len(e) > 0 {
+		return e
+	}
+
+	return nil
+}
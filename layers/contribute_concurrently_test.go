@@ -0,0 +1,99 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	layersBp "github.com/buildpacks/libbuildpack/v2/layers"
+	loggerBp "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/internal"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestContributeConcurrently(t *testing.T) {
+	spec.Run(t, "ContributeConcurrently", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		it("calls all contributors", func() {
+			called := make([]bool, 3)
+
+			g.Expect(layers.ContributeConcurrently(
+				func() error { called[0] = true; return nil },
+				func() error { called[1] = true; return nil },
+				func() error { called[2] = true; return nil },
+			)).To(gomega.Succeed())
+
+			g.Expect(called).To(gomega.Equal([]bool{true, true, true}))
+		})
+
+		it("joins errors", func() {
+			err := layers.ContributeConcurrently(
+				func() error { return fmt.Errorf("test-error-1") },
+				func() error { return nil },
+				func() error { return fmt.Errorf("test-error-2") },
+			)
+
+			g.Expect(err).To(gomega.MatchError("test-error-1\ntest-error-2"))
+			g.Expect(err).To(gomega.HaveLen(2))
+		})
+
+		it("contributes dependency layers concurrently", func() {
+			root := test.ScratchDir(t, "contribute-concurrently")
+			logger := logger.Logger{Logger: loggerBp.NewLogger(ioutil.Discard, ioutil.Discard)}
+			ls := layers.NewLayers(layersBp.Layers{Root: root}, layersBp.Layers{}, buildpack.Buildpack{}, logger)
+
+			var contributors []func() error
+			for i := 0; i < 20; i++ {
+				d := buildpack.Dependency{
+					ID:      fmt.Sprintf("test-id-%d", i),
+					Version: internal.NewTestVersion(t, "1.0"),
+					SHA256:  fmt.Sprintf("test-sha256-%d", i),
+					URI:     "https://test.com/test-path",
+				}
+
+				test.WriteFile(t, filepath.Join(root, fmt.Sprintf("%s.toml", d.SHA256)), `[metadata]
+ID = "%s"
+Version = "%s"
+SHA256 = "%s"
+URI = "%s"`, d.ID, d.Version.Original(), d.SHA256, d.URI)
+
+				l := ls.DependencyLayer(d)
+				contributors = append(contributors, func() error {
+					return l.Contribute(func(artifact string, layer layers.DependencyLayer) error {
+						return layer.OverrideLaunchEnv("TEST_KEY", "test-value")
+					}, layers.Launch)
+				})
+			}
+
+			g.Expect(layers.ContributeConcurrently(contributors...)).To(gomega.Succeed())
+
+			g.Expect(ls.Plans.Entries).To(gomega.HaveLen(20))
+			g.Expect(ls.TouchedLayers.Unused()).To(gomega.BeEmpty())
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -19,6 +19,7 @@ package layers
 import (
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
@@ -34,7 +35,9 @@ type DependencyLayer struct {
 
 	downloadLayer DownloadLayer
 	logger        logger.Logger
+	buildPlans    *buildpackplan.Plans
 	plans         *buildpackplan.Plans
+	plansMutex    *sync.Mutex
 }
 
 // ArtifactName returns the name portion of the download path for the dependency.
@@ -47,16 +50,19 @@ type DependencyLayerContributor func(artifact string, layer DependencyLayer) err
 
 // Contribute facilitates custom contribution of an artifact to a layer.  If the artifact has already been contributed,
 // the contribution is validated and the contributor is not called.  If the contribution is out of date, the layer is
-// completely removed before contribution occurs.
+// completely removed before contribution occurs.  Warnings are logged if the dependency is near or past its deprecation
+// date or end-of-life, or is affected by known CVEs.  Any download of the artifact is charged to this layer's
+// statistics.
 func (l DependencyLayer) Contribute(contributor DependencyLayerContributor, flags ...Flag) error {
 	l.downloadLayer.Touch()
+	l.Dependency.LogLifecycleWarnings(l.logger)
 
 	if err := l.Layer.Contribute(l.Dependency, func(layer Layer) error {
 		if err := os.RemoveAll(l.Root); err != nil {
 			return err
 		}
 
-		a, err := l.downloadLayer.Artifact()
+		a, err := l.downloadLayer.artifact(filepath.Base(l.Root))
 		if err != nil {
 			return err
 		}
@@ -66,34 +72,46 @@ func (l DependencyLayer) Contribute(contributor DependencyLayerContributor, flag
 		return err
 	}
 
-	if l.contains(flags, Launch) {
+	if containsFlag(flags, Build) {
+		l.contributeToBuildBillOfMaterials()
+	}
+
+	if containsFlag(flags, Launch) {
 		l.contributeToBuildPlan()
 	}
 	return nil
 }
 
-func (*DependencyLayer) contains(flags []Flag, candidate Flag) bool {
-	for _, f := range flags {
-		if f == candidate {
-			return true
-		}
-	}
-
-	return false
+func (l *DependencyLayer) contributeToBuildBillOfMaterials() {
+	l.logger.Debug("Contributing %s to build bill-of-materials", l.Dependency.ID)
+	appendPlans(l.plansMutex, l.buildPlans, dependencyPlan(l.Dependency))
 }
 
 func (l *DependencyLayer) contributeToBuildPlan() {
 	l.logger.Debug("Contributing %s to bill-of-materials", l.Dependency.ID)
+	appendPlans(l.plansMutex, l.plans, dependencyPlan(l.Dependency))
+}
 
-	l.plans.Entries = append(l.plans.Entries, buildpackplan.Plan{
-		Name:    l.Dependency.ID,
-		Version: l.Dependency.Version.Original(),
+func dependencyPlan(dependency buildpack.Dependency) buildpackplan.Plan {
+	p := buildpackplan.Plan{
+		Name:    dependency.ID,
+		Version: dependency.Version.Original(),
 		Metadata: buildpackplan.Metadata{
-			"name":     l.Dependency.Name,
-			"uri":      l.Dependency.URI,
-			"sha256":   l.Dependency.SHA256,
-			"stacks":   l.Dependency.Stacks,
-			"licenses": l.Dependency.Licenses,
+			"name":     dependency.Name,
+			"uri":      dependency.URI,
+			"sha256":   dependency.SHA256,
+			"stacks":   dependency.Stacks,
+			"licenses": dependency.Licenses,
 		},
-	})
+	}
+
+	if dependency.OS != "" {
+		p.Metadata["os"] = dependency.OS
+	}
+
+	if dependency.Arch != "" {
+		p.Metadata["arch"] = dependency.Arch
+	}
+
+	return p
 }
@@ -17,12 +17,15 @@
 package layers_test
 
 import (
+	"bytes"
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
 	buildpackplanBp "github.com/buildpacks/libbuildpack/v2/buildpackplan"
 	layersBp "github.com/buildpacks/libbuildpack/v2/layers"
+	loggerBp "github.com/buildpacks/libbuildpack/v2/logger"
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
 	"github.com/cloudfoundry/libcfbuildpack/v2/internal"
@@ -96,6 +99,47 @@ URI = "%s"`, dependency.ID, dependency.Version.Original(), dependency.SHA256, de
 			g.Expect(contributed).To(gomega.BeFalse())
 		})
 
+		it("reuses cached layer for dependency with lifecycle metadata", func() {
+			eol := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+			dependency.EOL = &eol
+			dependency.CVEs = []string{"CVE-2020-0001"}
+			layer = ls.DependencyLayer(dependency)
+
+			g.Expect(layer.WriteMetadata(dependency)).To(gomega.Succeed())
+
+			contributed := false
+			g.Expect(layer.Contribute(func(artifact string, layer layers.DependencyLayer) error {
+				contributed = true
+				return nil
+			})).To(gomega.Succeed())
+
+			g.Expect(contributed).To(gomega.BeFalse())
+		})
+
+		it("logs lifecycle warnings", func() {
+			var info bytes.Buffer
+
+			eol := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+			dependency.EOL = &eol
+			ls = layers.NewLayers(layersBp.Layers{Root: root}, layersBp.Layers{}, buildpack.Buildpack{},
+				logger.Logger{Logger: loggerBp.NewLogger(nil, &info)})
+			layer = ls.DependencyLayer(dependency)
+
+			test.WriteFile(t, layer.Metadata, `[metadata]
+ID = "%s"
+Version = "%s"
+SHA256 = "%s"
+URI = "%s"
+eol = 2020-01-01T00:00:00Z`, dependency.ID, dependency.Version.Original(), dependency.SHA256, dependency.URI)
+
+			g.Expect(layer.Contribute(func(artifact string, layer layers.DependencyLayer) error {
+				return nil
+			})).To(gomega.Succeed())
+
+			g.Expect(info.String()).To(gomega.ContainSubstring("test-id 1.0 has lifecycle warnings"))
+			g.Expect(info.String()).To(gomega.ContainSubstring("end-of-life since 2020-01-01"))
+		})
+
 		it("returns artifact name", func() {
 			g.Expect(layer.ArtifactName()).To(gomega.Equal("test-path"))
 		})
@@ -144,6 +188,50 @@ URI = "%s"`, dependency.ID, dependency.Version.Original(), dependency.SHA256, de
 			g.Expect(*ls.Plans).To(gomega.Equal(buildpackplan.Plans{}))
 		})
 
+		it("contributes build dependency to build bill-of-materials", func() {
+			test.WriteFile(t, layer.Metadata, `[metadata]
+ID = "%s"
+Version = "%s"
+SHA256 = "%s"
+URI = "%s"`, dependency.ID, dependency.Version.Original(), dependency.SHA256, dependency.URI)
+
+			g.Expect(layer.Contribute(func(artifact string, layer layers.DependencyLayer) error {
+				return nil
+			}, layers.Build)).To(gomega.Succeed())
+
+			g.Expect(*ls.BuildPlans).To(gomega.Equal(buildpackplan.Plans{
+				Plans: buildpackplanBp.Plans{
+					Entries: []buildpackplan.Plan{
+						{
+							Name:    dependency.ID,
+							Version: "1.0",
+							Metadata: buildpackplan.Metadata{
+								"name":     dependency.Name,
+								"uri":      dependency.URI,
+								"sha256":   dependency.SHA256,
+								"stacks":   dependency.Stacks,
+								"licenses": dependency.Licenses,
+							},
+						},
+					},
+				},
+			}))
+		})
+
+		it("does not contribute launch dependency to build bill-of-materials", func() {
+			test.WriteFile(t, layer.Metadata, `[metadata]
+ID = "%s"
+Version = "%s"
+SHA256 = "%s"
+URI = "%s"`, dependency.ID, dependency.Version.Original(), dependency.SHA256, dependency.URI)
+
+			g.Expect(layer.Contribute(func(artifact string, layer layers.DependencyLayer) error {
+				return nil
+			}, layers.Launch)).To(gomega.Succeed())
+
+			g.Expect(*ls.BuildPlans).To(gomega.Equal(buildpackplan.Plans{}))
+		})
+
 		it("cleans layer when contributing dependency layer", func() {
 			test.WriteFile(t, filepath.Join(root, fmt.Sprintf("%s.toml", dependency.SHA256)), `[metadata]
 ID = "%s"
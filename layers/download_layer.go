@@ -27,6 +27,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
 	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
@@ -50,7 +51,15 @@ type DownloadLayer struct {
 // will be validated and used directly.  If the artifact is out of date, the layer is left untouched and the contributor
 // is responsible for cleaning the layer if necessary.
 func (l DownloadLayer) Artifact() (string, error) {
+	return l.artifact(filepath.Base(l.Root))
+}
+
+// artifact returns the path to the artifact, recording its statistics against the named layer.  If the name is not the
+// name of this layer, the download is charged to that layer and only the downloaded bytes are recorded, as the time is
+// already part of that layer's contribution.
+func (l DownloadLayer) artifact(name string) (string, error) {
 	l.Touch()
+	start := time.Now()
 
 	matches, err := l.cacheLayer.MetadataMatches(l.dependency)
 	if err != nil {
@@ -60,6 +69,7 @@ func (l DownloadLayer) Artifact() (string, error) {
 	artifact := filepath.Join(l.cacheLayer.Root, filepath.Base(l.dependency.URI))
 	if matches {
 		l.logger.Body("%s cached download from buildpack", color.GreenString("Reusing"))
+		l.recordStatistics(name, start, artifact, false)
 		return artifact, nil
 	}
 
@@ -71,6 +81,7 @@ func (l DownloadLayer) Artifact() (string, error) {
 	artifact = filepath.Join(l.Root, filepath.Base(l.dependency.URI))
 	if matches {
 		l.logger.Body("%s cached download from previous build", color.GreenString("Reusing"))
+		l.recordStatistics(name, start, artifact, false)
 		return artifact, nil
 	}
 
@@ -92,6 +103,7 @@ func (l DownloadLayer) Artifact() (string, error) {
 		return "", err
 	}
 
+	l.recordStatistics(name, start, artifact, true)
 	return artifact, nil
 }
 
@@ -173,3 +185,38 @@ func (l DownloadLayer) verify(file string) error {
 	}
 	return nil
 }
+
+func (l DownloadLayer) recordStatistics(name string, start time.Time, artifact string, downloaded bool) {
+	if l.statistics == nil {
+		return
+	}
+
+	charged := name != filepath.Base(l.Root)
+	if charged && !downloaded {
+		return
+	}
+
+	duration := time.Since(start)
+
+	var size int64
+	if i, err := os.Stat(artifact); err != nil {
+		l.logger.Debug("Unable to determine size of %s: %s", artifact, err.Error())
+	} else {
+		size = i.Size()
+	}
+
+	l.statistics.record(name, func(statistic *LayerStatistic) {
+		if charged {
+			statistic.Downloaded += size
+			return
+		}
+
+		statistic.Cached = !downloaded
+		statistic.Duration = duration
+		statistic.Size = size
+
+		if downloaded {
+			statistic.Downloaded = size
+		}
+	})
+}
@@ -19,6 +19,7 @@ package layers
 import (
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
@@ -32,10 +33,11 @@ type HelperLayer struct {
 	// ID is the id of the buildpack provided helper.
 	ID string
 
-	buildpack buildpack.Buildpack
-	logger    logger.Logger
-	name      string
-	plans     *buildpackplan.Plans
+	buildpack  buildpack.Buildpack
+	logger     logger.Logger
+	name       string
+	plans      *buildpackplan.Plans
+	plansMutex *sync.Mutex
 }
 
 // HelperLayerContributor defines a callback function that is called when a buildpack provided helper needs to be
@@ -65,7 +67,7 @@ func (l HelperLayer) Contribute(contributor HelperLayerContributor, flags ...Fla
 func (l *HelperLayer) contributeToBuildPlan() {
 	l.logger.Debug("Contributing %s to bill-of-materials", l.ID)
 
-	l.plans.Entries = append(l.plans.Entries, buildpackplan.Plan{
+	appendPlans(l.plansMutex, l.plans, buildpackplan.Plan{
 		Name:    l.ID,
 		Version: l.buildpack.Info.Version,
 		Metadata: buildpackplan.Metadata{
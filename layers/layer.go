@@ -17,7 +17,9 @@
 package layers
 
 import (
+	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/buildpacks/libbuildpack/v2/layers"
 	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
@@ -34,6 +36,7 @@ type Layer struct {
 	Logger logger.Logger
 
 	touchedLayers TouchedLayers
+	statistics    *Statistics
 }
 
 // AppendBuildEnv appends the value of this environment variable to any previous declarations of the value without any
@@ -176,9 +179,11 @@ type LayerContributor func(layer Layer) error
 
 // Contribute facilitates custom contribution of a layer.  If the layer has already been contributed, the contribution
 // is validated and the contributor is not called.  If the contribution is out of date, the layer is
-// // completely removed before contribution occurs.
+// // completely removed before contribution occurs.  If the Reproducible flag is specified, the layer is normalized
+// after contribution.
 func (l Layer) Contribute(expected logger.Identifiable, contributor LayerContributor, flags ...Flag) error {
 	l.Touch()
+	start := time.Now()
 
 	matches, err := l.MetadataMatches(expected)
 	if err != nil {
@@ -188,7 +193,13 @@ func (l Layer) Contribute(expected logger.Identifiable, contributor LayerContrib
 	if matches {
 		l.Logger.Header("%s: %s cached layer",
 			l.prettyIdentity(expected), color.GreenString("Reusing"))
-		return l.WriteMetadata(expected, flags...)
+
+		if err := l.WriteMetadata(expected, flags...); err != nil {
+			return err
+		}
+
+		l.recordStatistics(start, true)
+		return nil
 	}
 
 	l.Logger.Header("%s: %s to layer",
@@ -199,7 +210,18 @@ func (l Layer) Contribute(expected logger.Identifiable, contributor LayerContrib
 		return err
 	}
 
-	return l.WriteMetadata(expected, flags...)
+	if containsFlag(flags, Reproducible) {
+		if err := l.Normalize(); err != nil {
+			return err
+		}
+	}
+
+	if err := l.WriteMetadata(expected, flags...); err != nil {
+		return err
+	}
+
+	l.recordStatistics(start, false)
+	return nil
 }
 
 // MetadataMatches compares the expected metadata for the actual metadata of this layer.
@@ -240,6 +262,35 @@ func (l Layer) WriteProfile(file string, format string, args ...interface{}) err
 	return l.Layer.WriteProfile(file, format, args...)
 }
 
+func (l Layer) recordStatistics(start time.Time, cached bool) {
+	if l.statistics == nil {
+		return
+	}
+
+	duration := time.Since(start)
+
+	size, err := diskSize(l.Root)
+	if err != nil {
+		l.Logger.Debug("Unable to determine size of %s: %s", l.Root, err.Error())
+	}
+
+	l.statistics.record(filepath.Base(l.Root), func(statistic *LayerStatistic) {
+		statistic.Cached = cached
+		statistic.Duration = duration
+		statistic.Size = size
+	})
+}
+
+func containsFlag(flags []Flag, candidate Flag) bool {
+	for _, f := range flags {
+		if f == candidate {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (Layer) prettyIdentity(v logger.Identifiable) string {
 	if v == nil {
 		return ""
@@ -18,8 +18,12 @@ package layers
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 
+	"github.com/BurntSushi/toml"
 	"github.com/buildpacks/libbuildpack/v2/layers"
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
@@ -31,15 +35,22 @@ import (
 type Layers struct {
 	layers.Layers
 
+	// BuildPlans contains all contributed dependencies that are available at build time.
+	BuildPlans *buildpackplan.Plans
+
 	// Plans contains all contributed dependencies.
 	Plans *buildpackplan.Plans
 
+	// Statistics records the time and space used by layers contributed during this execution.
+	Statistics *Statistics
+
 	// TouchedLayers registers the layers that have been touched during this execution.
 	TouchedLayers TouchedLayers
 
 	buildpack      buildpack.Buildpack
 	buildpackCache layers.Layers
 	logger         logger.Logger
+	plansMutex     *sync.Mutex
 }
 
 // DependencyLayer returns a DependencyLayer unique to a dependency.
@@ -54,7 +65,9 @@ func (l Layers) DependencyLayerWithID(id string, dependency buildpack.Dependency
 		dependency,
 		l.DownloadLayer(dependency),
 		l.logger,
+		l.BuildPlans,
 		l.Plans,
+		l.plansMutex,
 	}
 }
 
@@ -62,7 +75,7 @@ func (l Layers) DependencyLayerWithID(id string, dependency buildpack.Dependency
 func (l Layers) DownloadLayer(dependency buildpack.Dependency) DownloadLayer {
 	return DownloadLayer{
 		l.Layer(dependency.SHA256),
-		Layer{l.buildpackCache.Layer(dependency.SHA256), l.logger, l.TouchedLayers},
+		Layer{l.buildpackCache.Layer(dependency.SHA256), l.logger, l.TouchedLayers, nil},
 		dependency,
 		l.buildpack.Info,
 		l.logger,
@@ -78,12 +91,13 @@ func (l Layers) HelperLayer(id string, name string) HelperLayer {
 		l.logger,
 		name,
 		l.Plans,
+		l.plansMutex,
 	}
 }
 
 // Layer creates a Layer with a specified name.
 func (l Layers) Layer(name string) Layer {
-	return Layer{l.Layers.Layer(name), l.logger, l.TouchedLayers}
+	return Layer{l.Layers.Layer(name), l.logger, l.TouchedLayers, l.Statistics}
 }
 
 // MultiDependencyLayer returns a MultiDependencyLayer unique to a collection of dependencies.
@@ -99,7 +113,9 @@ func (l Layers) MultiDependencyLayer(id string, dependencies ...buildpack.Depend
 		dependencies,
 		dl,
 		l.logger,
+		l.BuildPlans,
 		l.Plans,
+		l.plansMutex,
 	}
 }
 
@@ -119,20 +135,100 @@ func (l Layers) WriteApplicationMetadata(metadata Metadata) error {
 
 		max := l.maximumTypeLength(p)
 		for _, p := range p {
+			command := strings.Join(append([]string{p.Command}, p.Args...), " ")
 			format := fmt.Sprintf("%%s%%s:%%-%ds %%s", max-len(p.Type))
-			l.logger.Info(format, logger.BodyIndent, color.CyanString(p.Type), "", p.Command)
+			l.logger.Info(format, logger.BodyIndent, color.CyanString(p.Type), "", command)
+		}
+
+		conflicts, err := l.ProcessTypeConflicts(p)
+		if err != nil {
+			return err
+		}
+
+		if len(conflicts) > 0 {
+			l.logger.HeaderWarning("Process types declared by other buildpacks will be replaced:")
+			for _, c := range conflicts {
+				l.logger.BodyWarning(c.String())
+			}
 		}
 	}
 
 	return l.Layers.WriteApplicationMetadata(metadata)
 }
 
+// WriteProcessTypes validates process types and writes them, along with optional slices, as application metadata to
+// the filesystem.
+func (l Layers) WriteProcessTypes(processTypes *ProcessTypes, slices ...Slice) error {
+	p, err := processTypes.Processes()
+	if err != nil {
+		return err
+	}
+
+	return l.WriteApplicationMetadata(Metadata{Processes: p, Slices: slices})
+}
+
+// ProcessTypeConflicts returns the process types that have already been declared in the launch.toml of other
+// buildpacks in this build.  A buildpack that writes one of these process types will replace the other buildpack's
+// declaration.
+func (l Layers) ProcessTypeConflicts(processes Processes) ([]ProcessTypeConflict, error) {
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(l.Root), "*", "launch.toml"))
+	if err != nil {
+		return nil, err
+	}
+
+	types := make(map[string]bool, len(processes))
+	for _, p := range processes {
+		types[p.Type] = true
+	}
+
+	var conflicts []ProcessTypeConflict
+	for _, f := range files {
+		buildpack := filepath.Dir(f)
+		if buildpack == filepath.Clean(l.Root) {
+			continue
+		}
+
+		var m Metadata
+		if _, err := toml.DecodeFile(f, &m); err != nil {
+			l.logger.Debug("Unable to read application metadata %s: %s", f, err.Error())
+			continue
+		}
+
+		for _, p := range m.Processes {
+			if types[p.Type] {
+				conflicts = append(conflicts, ProcessTypeConflict{
+					Type:      p.Type,
+					Buildpack: filepath.Base(buildpack),
+					Command:   p.Command,
+				})
+			}
+		}
+	}
+
+	sort.Slice(conflicts, func(i int, j int) bool {
+		if conflicts[i].Type == conflicts[j].Type {
+			return conflicts[i].Buildpack < conflicts[j].Buildpack
+		}
+
+		return conflicts[i].Type < conflicts[j].Type
+	})
+
+	return conflicts, nil
+}
+
 // WritePersistentMetadata writes persistent metadata to the filesystem.
 func (l Layers) WritePersistentMetadata(metadata interface{}) error {
 	l.logger.Body("Writing persistent metadata")
 	return l.Layers.WritePersistentMetadata(metadata)
 }
 
+func appendPlans(mutex *sync.Mutex, plans *buildpackplan.Plans, entries ...buildpackplan.Plan) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	plans.Entries = append(plans.Entries, entries...)
+}
+
 func (l Layers) maximumTypeLength(processes Processes) int {
 	max := 0
 
@@ -149,10 +245,13 @@ func (l Layers) maximumTypeLength(processes Processes) int {
 func NewLayers(layers layers.Layers, buildpackCache layers.Layers, buildpack buildpack.Buildpack, logger logger.Logger) Layers {
 	return Layers{
 		Layers:         layers,
+		BuildPlans:     &buildpackplan.Plans{},
 		Plans:          &buildpackplan.Plans{},
+		Statistics:     NewStatistics(),
 		TouchedLayers:  NewTouchedLayers(layers.Root, logger),
 		buildpack:      buildpack,
 		buildpackCache: buildpackCache,
 		logger:         logger,
+		plansMutex:     &sync.Mutex{},
 	}
 }
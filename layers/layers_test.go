@@ -46,7 +46,7 @@ func TestLayers(t *testing.T) {
 		)
 
 		it.Before(func() {
-			root = test.ScratchDir(t, "layers")
+			root = filepath.Join(test.ScratchDir(t, "layers"), "buildpack")
 			logger := logger.Logger{Logger: loggerBp.NewLogger(nil, &info)}
 			l = layers.NewLayers(layersBp.Layers{Root: root}, layersBp.Layers{}, buildpack.Buildpack{}, logger)
 		})
@@ -67,6 +67,56 @@ func TestLayers(t *testing.T) {
 			g.Expect(actual).To(gomega.Equal(expected))
 		})
 
+		it("logs process type arguments", func() {
+			g.Expect(l.WriteApplicationMetadata(layers.Metadata{
+				Processes: []layers.Process{
+					{Type: "web", Command: "test-command", Args: []string{"test-arg-1", "test-arg-2"}},
+				},
+			})).To(gomega.Succeed())
+
+			g.Expect(info.String()).To(gomega.Equal(fmt.Sprintf(`  Process types:
+    %s: test-command test-arg-1 test-arg-2
+`, color.CyanString("web"))))
+		})
+
+		it("writes validated process types", func() {
+			g.Expect(l.WriteProcessTypes(layers.NewProcessTypes().Default("test-command"))).To(gomega.Succeed())
+
+			g.Expect(filepath.Join(l.Root, "launch.toml")).To(test.HaveContent(`[[processes]]
+  type = "web"
+  command = "test-command"
+  direct = false
+`))
+		})
+
+		it("does not write invalid process types", func() {
+			g.Expect(l.WriteProcessTypes(layers.NewProcessTypes().Default(""))).NotTo(gomega.Succeed())
+
+			g.Expect(filepath.Join(l.Root, "launch.toml")).NotTo(gomega.BeAnExistingFile())
+		})
+
+		it("detects process types declared by other buildpacks", func() {
+			root := test.ScratchDir(t, "layers")
+			logger := logger.Logger{Logger: loggerBp.NewLogger(nil, &info)}
+			l := layers.NewLayers(layersBp.Layers{Root: filepath.Join(root, "buildpack-a")}, layersBp.Layers{}, buildpack.Buildpack{}, logger)
+
+			test.WriteFile(t, filepath.Join(root, "buildpack-b", "launch.toml"), `[[processes]]
+type = "web"
+command = "other-command"
+
+[[processes]]
+type = "task"
+command = "other-task"
+`)
+
+			g.Expect(l.ProcessTypeConflicts(layers.Processes{
+				{Type: "web", Command: "test-command"},
+				{Type: "worker", Command: "test-worker"},
+			})).To(gomega.Equal([]layers.ProcessTypeConflict{
+				{Type: "web", Buildpack: "buildpack-b", Command: "other-command"},
+			}))
+		})
+
 		it("logs number of slices", func() {
 			g.Expect(l.WriteApplicationMetadata(layers.Metadata{
 				Slices: layers.Slices{
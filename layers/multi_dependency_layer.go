@@ -19,6 +19,7 @@ package layers
 import (
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
@@ -34,7 +35,9 @@ type MultiDependencyLayer struct {
 
 	downloadLayers map[string]DownloadLayer
 	logger         logger.Logger
+	buildPlans     *buildpackplan.Plans
 	plans          *buildpackplan.Plans
+	plansMutex     *sync.Mutex
 }
 
 // MultiDependencyLayerContributor defines a callback function that is called when a dependency needs to be contributed.
@@ -45,6 +48,10 @@ func (l MultiDependencyLayer) Contribute(contributors map[string]MultiDependency
 		v.Touch()
 	}
 
+	for _, d := range l.Dependencies {
+		d.LogLifecycleWarnings(l.logger)
+	}
+
 	if err := l.Layer.Contribute(metadata(l.Dependencies), func(layer Layer) error {
 		if err := os.RemoveAll(l.Root); err != nil {
 			return err
@@ -76,25 +83,25 @@ func (l MultiDependencyLayer) Contribute(contributors map[string]MultiDependency
 		return err
 	}
 
+	if containsFlag(flags, Build) {
+		l.contributeToBuildBillOfMaterials()
+	}
+
 	l.contributeToBuildPlan()
 	return nil
 }
 
+func (l *MultiDependencyLayer) contributeToBuildBillOfMaterials() {
+	for _, d := range l.Dependencies {
+		l.logger.Debug("Contributing %s to build bill-of-materials", d.ID)
+		appendPlans(l.plansMutex, l.buildPlans, dependencyPlan(d))
+	}
+}
+
 func (l *MultiDependencyLayer) contributeToBuildPlan() {
 	for _, d := range l.Dependencies {
 		l.logger.Debug("Contributing %s to bill-of-materials", d.ID)
-
-		l.plans.Entries = append(l.plans.Entries, buildpackplan.Plan{
-			Name:    d.ID,
-			Version: d.Version.Original(),
-			Metadata: buildpackplan.Metadata{
-				"name":     d.Name,
-				"uri":      d.URI,
-				"sha256":   d.SHA256,
-				"stacks":   d.Stacks,
-				"licenses": d.Licenses,
-			},
-		})
+		appendPlans(l.plansMutex, l.plans, dependencyPlan(d))
 	}
 }
 
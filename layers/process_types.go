@@ -0,0 +1,111 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultProcessType is the process type that is started when no explicit process type is requested.
+const DefaultProcessType = "web"
+
+var processType = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// ProcessTypes is a builder for a validated collection of Processes.
+type ProcessTypes struct {
+	processes Processes
+}
+
+// Add adds a process type whose command is run through a shell after profile.d scripts have been sourced.
+func (p *ProcessTypes) Add(processType string, command string, args ...string) *ProcessTypes {
+	p.processes = append(p.processes, Process{Type: processType, Command: command, Args: args})
+	return p
+}
+
+// AddDirect adds a process type whose command is exec'd directly by the OS without profile.d scripts being sourced.
+func (p *ProcessTypes) AddDirect(processType string, command string, args ...string) *ProcessTypes {
+	p.processes = append(p.processes, Process{Type: processType, Command: command, Args: args, Direct: true})
+	return p
+}
+
+// Default adds a process type with the DefaultProcessType name.
+func (p *ProcessTypes) Default(command string, args ...string) *ProcessTypes {
+	return p.Add(DefaultProcessType, command, args...)
+}
+
+// Processes validates the collection of process types and returns them.  All validation problems are reported in a
+// single error.
+func (p *ProcessTypes) Processes() (Processes, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return p.processes, nil
+}
+
+// Validate ensures that process type names are unique and contain only allowed characters and that every process type
+// has a command.
+func (p *ProcessTypes) Validate() error {
+	var problems []string
+
+	seen := make(map[string]bool, len(p.processes))
+	for _, process := range p.processes {
+		if !processType.MatchString(process.Type) {
+			problems = append(problems, fmt.Sprintf("process type %q must contain only letters, numbers, '.', '_', and '-'", process.Type))
+		}
+
+		if seen[process.Type] {
+			problems = append(problems, fmt.Sprintf("process type %q is declared more than once", process.Type))
+		}
+		seen[process.Type] = true
+
+		if strings.TrimSpace(process.Command) == "" {
+			problems = append(problems, fmt.Sprintf("process type %q must have a command", process.Type))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid process types:\n%s", strings.Join(problems, "\n"))
+	}
+
+	return nil
+}
+
+// NewProcessTypes creates a new, empty instance of ProcessTypes.
+func NewProcessTypes() *ProcessTypes {
+	return &ProcessTypes{}
+}
+
+// ProcessTypeConflict describes a process type that is declared by more than one buildpack.  Since later buildpacks
+// replace the process types of earlier buildpacks, only one of the declarations will be used at launch.
+type ProcessTypeConflict struct {
+	// Type is the conflicting process type.
+	Type string
+
+	// Buildpack is the layers directory name of the other buildpack declaring the process type.
+	Buildpack string
+
+	// Command is the command declared by the other buildpack.
+	Command string
+}
+
+// String makes ProcessTypeConflict satisfy the Stringer interface.
+func (p ProcessTypeConflict) String() string {
+	return fmt.Sprintf("%s (%s: %s)", p.Type, p.Buildpack, p.Command)
+}
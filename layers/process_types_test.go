@@ -0,0 +1,82 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestProcessTypes(t *testing.T) {
+	spec.Run(t, "ProcessTypes", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		it("builds processes", func() {
+			p, err := layers.NewProcessTypes().
+				Default("test-command-1").
+				Add("task", "test-command-2", "test-arg-1", "test-arg-2").
+				AddDirect("direct", "test-command-3").
+				Processes()
+
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p).To(gomega.Equal(layers.Processes{
+				{Type: "web", Command: "test-command-1"},
+				{Type: "task", Command: "test-command-2", Args: []string{"test-arg-1", "test-arg-2"}},
+				{Type: "direct", Command: "test-command-3", Direct: true},
+			}))
+		})
+
+		it("rejects duplicate process types", func() {
+			_, err := layers.NewProcessTypes().
+				Default("test-command-1").
+				Add("web", "test-command-2").
+				Processes()
+
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(`process type "web" is declared more than once`)))
+		})
+
+		it("rejects empty commands", func() {
+			_, err := layers.NewProcessTypes().Add("task", " ").Processes()
+
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(`process type "task" must have a command`)))
+		})
+
+		it("rejects invalid characters", func() {
+			_, err := layers.NewProcessTypes().Add("test type", "test-command").Processes()
+
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(`process type "test type" must contain only`)))
+		})
+
+		it("reports all problems", func() {
+			err := layers.NewProcessTypes().
+				Add("", "").
+				Add("web", "test-command").
+				Add("web", "test-command").
+				Validate()
+
+			g.Expect(err).To(gomega.MatchError(`invalid process types:
+process type "" must contain only letters, numbers, '.', '_', and '-'
+process type "" must have a command
+process type "web" is declared more than once`))
+		})
+	}, spec.Report(report.Terminal{}))
+}
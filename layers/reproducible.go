@@ -0,0 +1,137 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	// Reproducible indicates that a layer's contents should be normalized after contribution so that identical inputs
+	// produce an identical layer.
+	Reproducible Flag = 1 << 7
+
+	// SourceDateEpoch is the environment variable that specifies the modification time, in seconds since the Unix
+	// epoch, used when normalizing reproducible layers.
+	SourceDateEpoch = "SOURCE_DATE_EPOCH"
+)
+
+// DefaultModificationTime is the modification time used when normalizing reproducible layers if SOURCE_DATE_EPOCH is
+// not set.
+var DefaultModificationTime = time.Date(1980, time.January, 1, 0, 0, 1, 0, time.UTC)
+
+// BuildSpecificFiles are the patterns of files that are removed when normalizing reproducible layers.  Patterns are
+// matched against the base name of each file and directory using filepath.Match.
+var BuildSpecificFiles = []string{".DS_Store", "__pycache__", "*.pyc"}
+
+// Normalize normalizes the contents of a layer so that identical inputs produce an identical layer.  Files matching
+// BuildSpecificFiles are removed, directories are given 0755 permissions, files are given 0755 permissions if they
+// are executable by anyone and 0644 otherwise, and all modification times are set to SOURCE_DATE_EPOCH (or
+// DefaultModificationTime if it is not set).  Symbolic links are left untouched.
+func (l Layer) Normalize() error {
+	l.Logger.Body("Normalizing layer contents")
+
+	mtime, err := modificationTime()
+	if err != nil {
+		return err
+	}
+
+	if err := l.removeBuildSpecificFiles(); err != nil {
+		return err
+	}
+
+	return filepath.Walk(l.Root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == l.Root {
+				return nil
+			}
+
+			return err
+		}
+
+		mode := info.Mode()
+		if mode&os.ModeSymlink != 0 {
+			return nil
+		}
+
+		perm := os.FileMode(0644)
+		if mode.IsDir() || mode&0111 != 0 {
+			perm = 0755
+		}
+
+		if err := os.Chmod(path, perm); err != nil {
+			return err
+		}
+
+		return os.Chtimes(path, mtime, mtime)
+	})
+}
+
+func (l Layer) removeBuildSpecificFiles() error {
+	return filepath.Walk(l.Root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if path == l.Root {
+			return nil
+		}
+
+		for _, p := range BuildSpecificFiles {
+			if m, err := filepath.Match(p, info.Name()); err != nil {
+				return err
+			} else if !m {
+				continue
+			}
+
+			l.Logger.Debug("Removing build-specific file %s", path)
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		return nil
+	})
+}
+
+func modificationTime() (time.Time, error) {
+	s, ok := os.LookupEnv(SourceDateEpoch)
+	if !ok {
+		return DefaultModificationTime, nil
+	}
+
+	e, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be an integer number of seconds: %s", SourceDateEpoch, s)
+	}
+
+	return time.Unix(e, 0).UTC(), nil
+}
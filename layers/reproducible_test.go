@@ -0,0 +1,110 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	layersBp "github.com/buildpacks/libbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/internal"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestReproducible(t *testing.T) {
+	spec.Run(t, "Reproducible", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var layer layers.Layer
+
+		it.Before(func() {
+			root := test.ScratchDir(t, "reproducible")
+			layer = layers.NewLayers(layersBp.Layers{Root: root}, layersBp.Layers{}, buildpack.Buildpack{}, logger.Logger{}).
+				Layer("test-layer")
+		})
+
+		contribute := func(flags ...layers.Flag) error {
+			return layer.Contribute(nil, func(layer layers.Layer) error {
+				test.WriteFileWithPerm(t, filepath.Join(layer.Root, "bin", "test-executable"), 0700, "")
+				test.WriteFileWithPerm(t, filepath.Join(layer.Root, "test-file"), 0600, "")
+				test.TouchFile(t, layer.Root, "lib", "__pycache__", "test.cpython-38.pyc")
+				test.TouchFile(t, layer.Root, "lib", "test.pyc")
+				return nil
+			}, flags...)
+		}
+
+		it("normalizes layers with the reproducible flag", func() {
+			defer test.ReplaceEnv(t, layers.SourceDateEpoch, "1234567890")()
+
+			g.Expect(contribute(layers.Launch, layers.Reproducible)).To(gomega.Succeed())
+
+			expected := time.Unix(1234567890, 0)
+
+			for path, perm := range map[string]os.FileMode{
+				layer.Root:                       0755,
+				filepath.Join(layer.Root, "bin"): 0755,
+				filepath.Join(layer.Root, "bin", "test-executable"): 0755,
+				filepath.Join(layer.Root, "test-file"):              0644,
+				filepath.Join(layer.Root, "lib"):                    0755,
+			} {
+				i, err := os.Stat(path)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(i.Mode().Perm()).To(gomega.Equal(perm), path)
+				g.Expect(i.ModTime()).To(gomega.BeTemporally("==", expected), path)
+			}
+
+			g.Expect(filepath.Join(layer.Root, "lib", "__pycache__")).NotTo(gomega.BeAnExistingFile())
+			g.Expect(filepath.Join(layer.Root, "lib", "test.pyc")).NotTo(gomega.BeAnExistingFile())
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+		})
+
+		it("uses default modification time", func() {
+			defer internal.ProtectEnv(t, layers.SourceDateEpoch)()
+			g.Expect(os.Unsetenv(layers.SourceDateEpoch)).To(gomega.Succeed())
+
+			g.Expect(contribute(layers.Reproducible)).To(gomega.Succeed())
+
+			i, err := os.Stat(filepath.Join(layer.Root, "test-file"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(i.ModTime()).To(gomega.BeTemporally("==", layers.DefaultModificationTime))
+		})
+
+		it("rejects invalid SOURCE_DATE_EPOCH", func() {
+			defer test.ReplaceEnv(t, layers.SourceDateEpoch, "test-value")()
+
+			g.Expect(contribute(layers.Reproducible)).To(gomega.MatchError(gomega.ContainSubstring(layers.SourceDateEpoch)))
+		})
+
+		it("does not normalize layers without the reproducible flag", func() {
+			g.Expect(contribute()).To(gomega.Succeed())
+
+			i, err := os.Stat(filepath.Join(layer.Root, "test-file"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(i.Mode().Perm()).To(gomega.Equal(os.FileMode(0600)))
+			g.Expect(filepath.Join(layer.Root, "lib", "test.pyc")).To(gomega.BeAnExistingFile())
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+)
+
+// LayerStatistic records the time and space used by a single layer during this execution.
+type LayerStatistic struct {
+	// Name is the name of the layer.
+	Name string `json:"name"`
+
+	// Cached indicates whether the layer was reused from a previous build rather than contributed.
+	Cached bool `json:"cached"`
+
+	// Downloaded is the number of bytes downloaded into the layer.
+	Downloaded int64 `json:"downloaded"`
+
+	// Duration is the wall time spent contributing the layer.
+	Duration time.Duration `json:"duration"`
+
+	// Size is the number of bytes used by the layer on disk.
+	Size int64 `json:"size"`
+}
+
+// Statistics collects LayerStatistics for all of the layers contributed during this execution.  It is safe for
+// concurrent use.
+type Statistics struct {
+	// File is the path to write a JSON representation of the statistics to.  If empty, no file is written.
+	File string
+
+	mutex   sync.Mutex
+	entries map[string]*LayerStatistic
+}
+
+// Entries returns all recorded statistics, sorted by layer name.
+func (s *Statistics) Entries() []LayerStatistic {
+	if s == nil {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var e []LayerStatistic
+	for _, v := range s.entries {
+		e = append(e, *v)
+	}
+
+	sort.Slice(e, func(i int, j int) bool {
+		return e[i].Name < e[j].Name
+	})
+
+	return e
+}
+
+// Log prints a summary table of all recorded statistics.
+func (s *Statistics) Log(logger logger.Logger) {
+	e := s.Entries()
+	if len(e) == 0 {
+		return
+	}
+
+	max := len("layer")
+	for _, v := range e {
+		if l := len(v.Name); l > max {
+			max = l
+		}
+	}
+
+	format := fmt.Sprintf("%%-%ds  %%10s  %%10s  %%10s  %%s", max)
+
+	logger.Header("Layer statistics:")
+	logger.Body(format, "layer", "time", "downloaded", "size", "")
+
+	var duration time.Duration
+	var downloaded, size int64
+	for _, v := range e {
+		status := "contributed"
+		if v.Cached {
+			status = "cached"
+		}
+
+		logger.Body(format, v.Name, v.Duration.Round(time.Millisecond), formatBytes(v.Downloaded), formatBytes(v.Size), status)
+
+		duration += v.Duration
+		downloaded += v.Downloaded
+		size += v.Size
+	}
+
+	logger.Body(format, "total", duration.Round(time.Millisecond), formatBytes(downloaded), formatBytes(size), "")
+}
+
+// Write writes a JSON representation of all recorded statistics to File.  If File is empty, nothing is written.
+func (s *Statistics) Write() error {
+	if s == nil || s.File == "" {
+		return nil
+	}
+
+	return writeJSON(s.File, s.Entries())
+}
+
+func (s *Statistics) record(name string, f func(statistic *LayerStatistic)) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.entries == nil {
+		s.entries = make(map[string]*LayerStatistic)
+	}
+
+	e, ok := s.entries[name]
+	if !ok {
+		e = &LayerStatistic{Name: name}
+		s.entries[name] = e
+	}
+
+	f(e)
+}
+
+func diskSize(root string) (int64, error) {
+	var size int64
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size, err
+}
+
+func formatBytes(b int64) string {
+	const unit = 1024
+
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+// NewStatistics creates a new, empty instance of Statistics.
+func NewStatistics() *Statistics {
+	return &Statistics{entries: make(map[string]*LayerStatistic)}
+}
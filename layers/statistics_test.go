@@ -0,0 +1,171 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package layers_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	layersBp "github.com/buildpacks/libbuildpack/v2/layers"
+	loggerBp "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/internal"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestStatistics(t *testing.T) {
+	spec.Run(t, "Statistics", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			root string
+			info bytes.Buffer
+			log  logger.Logger
+			ls   layers.Layers
+		)
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "statistics")
+			info.Reset()
+			log = logger.Logger{Logger: loggerBp.NewLogger(nil, &info)}
+			ls = layers.NewLayers(layersBp.Layers{Root: root}, layersBp.Layers{}, buildpack.Buildpack{}, log)
+		})
+
+		it("records contributed layers", func() {
+			g.Expect(ls.Layer("test-layer").Contribute(nil, func(layer layers.Layer) error {
+				test.WriteFile(t, filepath.Join(layer.Root, "test-file"), "0123456789")
+				return nil
+			})).To(gomega.Succeed())
+
+			e := ls.Statistics.Entries()
+			g.Expect(e).To(gomega.HaveLen(1))
+			g.Expect(e[0].Name).To(gomega.Equal("test-layer"))
+			g.Expect(e[0].Cached).To(gomega.BeFalse())
+			g.Expect(e[0].Size).To(gomega.Equal(int64(10)))
+		})
+
+		it("records cached layers", func() {
+			test.WriteFile(t, filepath.Join(root, "test-layer.toml"), `[metadata]
+Name = "test-name"`)
+
+			g.Expect(ls.Layer("test-layer").Contribute(identifiable{Name: "test-name"}, func(layer layers.Layer) error {
+				return nil
+			})).To(gomega.Succeed())
+
+			e := ls.Statistics.Entries()
+			g.Expect(e).To(gomega.HaveLen(1))
+			g.Expect(e[0].Cached).To(gomega.BeTrue())
+		})
+
+		it("charges downloads to dependency layers", func() {
+			server := ghttp.NewServer()
+			defer server.Close()
+			server.AppendHandlers(ghttp.RespondWith(http.StatusOK, "test-payload"))
+
+			dependency := buildpack.Dependency{
+				ID:      "test-id",
+				Version: internal.NewTestVersion(t, "1.0"),
+				SHA256:  "6f06dd0e26608013eff30bb1e951cda7de3fdd9e78e907470e0dd5c0ed25e273",
+				URI:     fmt.Sprintf("%s/test-path", server.URL()),
+			}
+
+			g.Expect(ls.DependencyLayer(dependency).Contribute(func(artifact string, layer layers.DependencyLayer) error {
+				test.WriteFile(t, filepath.Join(layer.Root, "test-file"), "0123456789")
+				return nil
+			})).To(gomega.Succeed())
+
+			e := ls.Statistics.Entries()
+			g.Expect(e).To(gomega.HaveLen(1))
+			g.Expect(e[0].Name).To(gomega.Equal("test-id"))
+			g.Expect(e[0].Cached).To(gomega.BeFalse())
+			g.Expect(e[0].Downloaded).To(gomega.Equal(int64(len("test-payload"))))
+			g.Expect(e[0].Size).To(gomega.Equal(int64(10)))
+
+			info.Reset()
+			ls.Statistics.Log(log)
+			g.Expect(info.String()).To(gomega.MatchRegexp(`total\s+\S+\s+12 B\s+10 B`))
+		})
+
+		it("logs summary", func() {
+			s := layers.NewStatistics()
+			g.Expect(ls.Layer("test-layer").Contribute(nil, func(layer layers.Layer) error {
+				test.WriteFile(t, filepath.Join(layer.Root, "test-file"), "%2048s", "")
+				return nil
+			})).To(gomega.Succeed())
+			info.Reset()
+
+			ls.Statistics.Log(log)
+
+			g.Expect(info.String()).To(gomega.ContainSubstring("Layer statistics:"))
+			g.Expect(info.String()).To(gomega.MatchRegexp(`test-layer\s+\S+\s+0 B\s+2\.0 KiB\s+contributed`))
+			g.Expect(info.String()).To(gomega.MatchRegexp(`total\s+\S+\s+0 B\s+2\.0 KiB`))
+
+			info.Reset()
+			s.Log(log)
+			g.Expect(info.String()).To(gomega.BeEmpty())
+		})
+
+		it("writes JSON", func() {
+			g.Expect(ls.Layer("test-layer").Contribute(nil, func(layer layers.Layer) error {
+				return nil
+			})).To(gomega.Succeed())
+
+			ls.Statistics.File = filepath.Join(root, "statistics.json")
+			g.Expect(ls.Statistics.Write()).To(gomega.Succeed())
+
+			b, err := ioutil.ReadFile(ls.Statistics.File)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(b).To(gomega.MatchJSON(`[
+  {
+    "name": "test-layer",
+    "cached": false,
+    "downloaded": 0,
+    "duration": ` + durationOf(ls.Statistics.Entries()[0].Duration) + `,
+    "size": 0
+  }
+]`))
+		})
+
+		it("does not write JSON without a file", func() {
+			g.Expect(ls.Statistics.Write()).To(gomega.Succeed())
+		})
+	}, spec.Report(report.Terminal{}))
+}
+
+type identifiable struct {
+	Name string
+}
+
+func (i identifiable) Identity() (string, string) {
+	return i.Name, ""
+}
+
+func durationOf(d time.Duration) string {
+	return fmt.Sprintf("%d", d.Nanoseconds())
+}
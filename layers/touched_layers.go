@@ -19,20 +19,39 @@ package layers
 import (
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/BurntSushi/toml"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
 	"github.com/cloudfoundry/libcfbuildpack/v2/internal"
 	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
 	"github.com/heroku/color"
 )
 
+// UntouchedLayersMetadata is the key in persistent metadata that records how many consecutive builds each retained
+// cache layer has gone untouched.  The counts are read when the TouchedLayers is created and merged into the persistent
+// metadata during Cleanup, so they survive buildpacks rewriting their persistent metadata.
+const UntouchedLayersMetadata = "untouched-layers"
+
 // TouchedLayers contains information about the layers that have been touched as part of this execution.
 type TouchedLayers struct {
 	// Root is the root location of all layers to inspect for unused layers.
 	Root string
 
-	logger  logger.Logger
-	touched internal.Set
+	// DryRun indicates that Cleanup should only report the layers it would remove, without removing them.
+	DryRun bool
+
+	// Keep are the names of layers that should never be removed, even if they have not been touched.
+	Keep []string
+
+	// RetainCacheLayers is the number of consecutive builds that an untouched cache layer is retained for before it is
+	// removed.  The count for each layer is tracked in persistent metadata.
+	RetainCacheLayers int
+
+	logger    logger.Logger
+	touched   internal.Set
+	untouched map[string]int64
 }
 
 // Add registers that a given layer has been touched
@@ -41,27 +60,77 @@ func (t TouchedLayers) Add(metadata string) {
 	t.touched.Add(metadata)
 }
 
-// Cleanup removes all layers that have not been touched as part of this execution.
+// Cleanup removes all layers that have not been touched as part of this execution.  Layers listed in Keep are never
+// removed and untouched cache layers are retained for RetainCacheLayers builds.  If DryRun is set, layers that would be
+// removed are reported but not removed.
 func (t TouchedLayers) Cleanup() error {
-	candidates, err := t.candidates()
+	unused, err := t.unused()
 	if err != nil {
 		return err
 	}
 
-	if t.logger.IsDebugEnabled() {
-		t.logger.Debug("Existing Layers: %s", candidates)
-		t.logger.Debug("Touched Layers: %s", t.touched)
+	previous := t.untouched
+
+	var remove []string
+	retained := make(map[string]int64)
+
+	for _, f := range unused {
+		name := t.name(f)
+
+		if t.keep(name) {
+			t.logger.Debug("Keeping untouched layer %s", name)
+			continue
+		}
+
+		if t.RetainCacheLayers > 0 {
+			cache, err := t.isCache(f)
+			if err != nil {
+				return err
+			}
+
+			if count := previous[name] + 1; cache && count <= int64(t.RetainCacheLayers) {
+				t.logger.Debug("Retaining untouched cache layer %s (%d of %d builds)", name, count, t.RetainCacheLayers)
+				retained[name] = count
+				continue
+			}
+		}
+
+		remove = append(remove, f)
 	}
 
-	remove := candidates.Difference(t.touched)
-	if remove.Size() == 0 {
+	if t.DryRun {
+		if len(remove) > 0 {
+			t.logger.Header("%s unused layers (dry run)", color.YellowString("Would remove"))
+			for _, f := range remove {
+				t.logger.Body(t.name(f))
+			}
+		}
+
+		return nil
+	}
+
+	if len(retained) > 0 || len(previous) > 0 {
+		if err := t.writeUntouchedCounts(retained); err != nil {
+			return err
+		}
+
+		if t.untouched != nil {
+			for k := range t.untouched {
+				delete(t.untouched, k)
+			}
+			for k, v := range retained {
+				t.untouched[k] = v
+			}
+		}
+	}
+
+	if len(remove) == 0 {
 		return nil
 	}
 
 	t.logger.Header("%s unused layers", color.YellowString("Removing"))
-	for r := range remove.Iterator() {
-		f := r.(string)
-		t.logger.Body(strings.TrimSuffix(filepath.Base(f), ".toml"))
+	for _, f := range remove {
+		t.logger.Body(t.name(f))
 
 		if err := os.RemoveAll(f); err != nil {
 			return err
@@ -71,6 +140,21 @@ func (t TouchedLayers) Cleanup() error {
 	return nil
 }
 
+// Unused returns the names of all layers that have not been touched as part of this execution.
+func (t TouchedLayers) Unused() ([]string, error) {
+	unused, err := t.unused()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, f := range unused {
+		names = append(names, t.name(f))
+	}
+
+	return names, nil
+}
+
 func (t TouchedLayers) candidates() (internal.Set, error) {
 	files, err := filepath.Glob(filepath.Join(t.Root, "*.toml"))
 	if err != nil {
@@ -90,7 +174,129 @@ func (t TouchedLayers) candidates() (internal.Set, error) {
 	return candidates, nil
 }
 
-// NewTouchedLayers creates a new instance that monitors a given root.
+func (t TouchedLayers) isCache(file string) (bool, error) {
+	var m struct {
+		Cache bool `toml:"cache"`
+	}
+
+	if _, err := toml.DecodeFile(file, &m); err != nil {
+		t.logger.Debug("Unable to read layer metadata %s: %s", file, err.Error())
+		return false, nil
+	}
+
+	return m.Cache, nil
+}
+
+func (t TouchedLayers) keep(name string) bool {
+	for _, k := range t.Keep {
+		if k == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (TouchedLayers) name(file string) string {
+	return strings.TrimSuffix(filepath.Base(file), ".toml")
+}
+
+func (t TouchedLayers) unused() ([]string, error) {
+	candidates, err := t.candidates()
+	if err != nil {
+		return nil, err
+	}
+
+	if t.logger.IsDebugEnabled() {
+		t.logger.Debug("Existing Layers: %s", candidates)
+		t.logger.Debug("Touched Layers: %s", t.touched)
+	}
+
+	var unused []string
+	for r := range candidates.Difference(t.touched).Iterator() {
+		unused = append(unused, r.(string))
+	}
+
+	sort.Strings(unused)
+	return unused, nil
+}
+
+func (t TouchedLayers) readStore() (map[string]interface{}, error) {
+	s := make(map[string]interface{})
+
+	f := filepath.Join(t.Root, "store.toml")
+	if exists, err := helper.FileExists(f); err != nil {
+		return nil, err
+	} else if !exists {
+		return s, nil
+	}
+
+	if _, err := toml.DecodeFile(f, &s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (t TouchedLayers) untouchedCounts() (map[string]int64, error) {
+	s, err := t.readStore()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64)
+
+	m, ok := s["metadata"].(map[string]interface{})
+	if !ok {
+		return counts, nil
+	}
+
+	u, ok := m[UntouchedLayersMetadata].(map[string]interface{})
+	if !ok {
+		return counts, nil
+	}
+
+	for k, v := range u {
+		if c, ok := v.(int64); ok {
+			counts[k] = c
+		}
+	}
+
+	return counts, nil
+}
+
+func (t TouchedLayers) writeUntouchedCounts(counts map[string]int64) error {
+	s, err := t.readStore()
+	if err != nil {
+		return err
+	}
+
+	m, ok := s["metadata"].(map[string]interface{})
+	if !ok {
+		m = make(map[string]interface{})
+	}
+
+	if len(counts) == 0 {
+		delete(m, UntouchedLayersMetadata)
+	} else {
+		m[UntouchedLayersMetadata] = counts
+	}
+
+	s["metadata"] = m
+	return internal.WriteTomlFile(filepath.Join(t.Root, "store.toml"), 0644, s)
+}
+
+// NewTouchedLayers creates a new instance that monitors a given root.  The untouched counts of retained cache layers are
+// read from persistent metadata as it was restored at the start of the build.
 func NewTouchedLayers(root string, logger logger.Logger) TouchedLayers {
-	return TouchedLayers{root, logger, internal.NewSet()}
+	t := TouchedLayers{Root: root, logger: logger, touched: internal.NewSet()}
+
+	untouched, err := t.untouchedCounts()
+	if err != nil {
+		logger.Debug("Unable to read untouched layer counts: %s", err.Error())
+		untouched = make(map[string]int64)
+	}
+	t.untouched = untouched
+
+	return t
 }
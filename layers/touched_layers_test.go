@@ -20,6 +20,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	bp "github.com/buildpacks/libbuildpack/v2/layers"
+	loggerBp "github.com/buildpacks/libbuildpack/v2/logger"
 	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
 	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
 	"github.com/cloudfoundry/libcfbuildpack/v2/test"
@@ -75,5 +77,102 @@ func TestTouchedLayers(t *testing.T) {
 
 			g.Expect(filepath.Join(root, "store.toml")).To(gomega.BeARegularFile())
 		})
+
+		it("reports unused layers", func() {
+			test.TouchFile(t, root, "test-layer-1.toml")
+			test.TouchFile(t, root, "test-layer-2.toml")
+
+			touched.Add(filepath.Join(root, "test-layer-1.toml"))
+
+			g.Expect(touched.Unused()).To(gomega.Equal([]string{"test-layer-2"}))
+		})
+
+		it("does not remove layers during a dry run", func() {
+			test.TouchFile(t, root, "test-layer.toml")
+			touched.DryRun = true
+
+			g.Expect(touched.Cleanup()).To(gomega.Succeed())
+
+			g.Expect(filepath.Join(root, "test-layer.toml")).To(gomega.BeARegularFile())
+		})
+
+		it("does not remove kept layers", func() {
+			test.TouchFile(t, root, "test-layer-1.toml")
+			test.TouchFile(t, root, "test-layer-2.toml")
+			touched.Keep = []string{"test-layer-1"}
+
+			g.Expect(touched.Cleanup()).To(gomega.Succeed())
+
+			g.Expect(filepath.Join(root, "test-layer-1.toml")).To(gomega.BeARegularFile())
+			g.Expect(filepath.Join(root, "test-layer-2.toml")).NotTo(gomega.BeARegularFile())
+		})
+
+		it("retains untouched cache layers", func() {
+			test.WriteFile(t, filepath.Join(root, "test-layer-1.toml"), "cache = true")
+			test.WriteFile(t, filepath.Join(root, "test-layer-2.toml"), "cache = false")
+			test.WriteFile(t, filepath.Join(root, "store.toml"), `[metadata]
+test-key = "test-value"
+`)
+			touched = layers.NewTouchedLayers(root, logger.Logger{})
+			touched.RetainCacheLayers = 2
+
+			g.Expect(touched.Cleanup()).To(gomega.Succeed())
+			g.Expect(filepath.Join(root, "test-layer-1.toml")).To(gomega.BeARegularFile())
+			g.Expect(filepath.Join(root, "test-layer-2.toml")).NotTo(gomega.BeARegularFile())
+			g.Expect(filepath.Join(root, "store.toml")).To(test.HaveContent(`[metadata]
+  test-key = "test-value"
+  [metadata.untouched-layers]
+    test-layer-1 = 1
+`))
+
+			g.Expect(touched.Cleanup()).To(gomega.Succeed())
+			g.Expect(filepath.Join(root, "test-layer-1.toml")).To(gomega.BeARegularFile())
+
+			g.Expect(touched.Cleanup()).To(gomega.Succeed())
+			g.Expect(filepath.Join(root, "test-layer-1.toml")).NotTo(gomega.BeARegularFile())
+			g.Expect(filepath.Join(root, "store.toml")).To(test.HaveContent(`[metadata]
+  test-key = "test-value"
+`))
+		})
+
+		it("keeps untouched counts across a restore when persistent metadata is rewritten", func() {
+			test.WriteFile(t, filepath.Join(root, "test-layer.toml"), "cache = true")
+			touched.RetainCacheLayers = 1
+
+			g.Expect(touched.Cleanup()).To(gomega.Succeed())
+			g.Expect(filepath.Join(root, "test-layer.toml")).To(gomega.BeARegularFile())
+
+			restored := test.ScratchDir(t, "touched-layers-restored")
+			test.CopyFile(t, filepath.Join(root, "store.toml"), filepath.Join(restored, "store.toml"))
+			test.CopyFile(t, filepath.Join(root, "test-layer.toml"), filepath.Join(restored, "test-layer.toml"))
+
+			touched = layers.NewTouchedLayers(restored, logger.Logger{})
+			touched.RetainCacheLayers = 1
+
+			g.Expect(bp.NewLayers(restored, loggerBp.Logger{}).
+				WritePersistentMetadata(map[string]interface{}{"test-key": "test-value"})).To(gomega.Succeed())
+			g.Expect(touched.Cleanup()).To(gomega.Succeed())
+
+			g.Expect(filepath.Join(restored, "test-layer.toml")).NotTo(gomega.BeARegularFile())
+			g.Expect(filepath.Join(restored, "store.toml")).To(test.HaveContent(`[metadata]
+  test-key = "test-value"
+`))
+		})
+
+		it("resets retention count of touched cache layers", func() {
+			test.WriteFile(t, filepath.Join(root, "test-layer.toml"), "cache = true")
+			test.WriteFile(t, filepath.Join(root, "store.toml"), `[metadata]
+  [metadata.untouched-layers]
+    test-layer = 1
+`)
+			touched = layers.NewTouchedLayers(root, logger.Logger{})
+			touched.RetainCacheLayers = 1
+			touched.Add(filepath.Join(root, "test-layer.toml"))
+
+			g.Expect(touched.Cleanup()).To(gomega.Succeed())
+			g.Expect(filepath.Join(root, "test-layer.toml")).To(gomega.BeARegularFile())
+			g.Expect(filepath.Join(root, "store.toml")).To(test.HaveContent(`[metadata]
+`))
+		})
 	}, spec.Report(report.Terminal{}))
 }
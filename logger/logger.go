@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/buildpacks/libbuildpack/v2/logger"
 	"github.com/heroku/color"
@@ -40,17 +41,46 @@ var (
 	lines            = regexp.MustCompile(`(?m)^`)
 	name             = color.New(color.FgBlue, color.Bold).SprintfFunc()
 	warning          = color.New(color.FgYellow, color.Bold).SprintfFunc()
+
+	// output serializes writes across all copies of a Logger since they share the same underlying writers.
+	output sync.Mutex
 )
 
 func init() {
 	color.Enabled()
 }
 
-// Logger is an extension to libbuildpack.Logger to add additional functionality.
+// Logger is an extension to libbuildpack.Logger to add additional functionality.  It is safe for concurrent use.
 type Logger struct {
 	logger.Logger
 }
 
+// Debug prints output to the configured debug writer, interpolating the format and any arguments and adding a newline
+// at the end.  If debug logging is not enabled, nothing is printed.
+func (l Logger) Debug(format string, args ...interface{}) {
+	if !l.IsDebugEnabled() {
+		return
+	}
+
+	output.Lock()
+	defer output.Unlock()
+
+	l.Logger.Debug(format, args...)
+}
+
+// Info prints output to the configured info writer, interpolating the format and any arguments and adding a newline
+// at the end.  If info logging is not enabled, nothing is printed.
+func (l Logger) Info(format string, args ...interface{}) {
+	if !l.IsInfoEnabled() {
+		return
+	}
+
+	output.Lock()
+	defer output.Unlock()
+
+	l.Logger.Info(format, args...)
+}
+
 // Title prints the buildpack description flush left, with an empty line above it.
 func (l Logger) Title(v Identifiable) {
 	if !l.IsInfoEnabled() {
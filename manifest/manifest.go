@@ -17,7 +17,10 @@
 package manifest
 
 import (
+	"archive/zip"
+	"io"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -40,22 +43,67 @@ func NewManifest(application application.Application, logger logger.Logger) (Man
 		return Manifest{properties.NewProperties()}, nil
 	}
 
-	b, err := ioutil.ReadFile(f)
+	in, err := os.Open(f)
 	if err != nil {
 		return Manifest{}, err
 	}
+	defer in.Close()
 
-	p, err := properties.LoadString(normalizeManifest(string(b)))
+	m, err := parse(in)
 	if err != nil {
 		return Manifest{}, err
 	}
 
-	m := Manifest{p}
-
 	logger.Debug("Manifest: %s", m)
 	return m, nil
 }
 
+// NewManifestFromJAR reads the META-INF/MANIFEST.MF entry of a JAR file.  If the JAR has no manifest, an empty
+// manifest is returned.
+func NewManifestFromJAR(path string, logger logger.Logger) (Manifest, error) {
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer z.Close()
+
+	for _, f := range z.File {
+		if f.Name != "META-INF/MANIFEST.MF" {
+			continue
+		}
+
+		in, err := f.Open()
+		if err != nil {
+			return Manifest{}, err
+		}
+		defer in.Close()
+
+		m, err := parse(in)
+		if err != nil {
+			return Manifest{}, err
+		}
+
+		logger.Debug("Manifest %s: %s", path, m)
+		return m, nil
+	}
+
+	return Manifest{properties.NewProperties()}, nil
+}
+
+func parse(in io.Reader) (Manifest, error) {
+	b, err := ioutil.ReadAll(in)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	p, err := properties.LoadString(normalizeManifest(string(b)))
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	return Manifest{p}, nil
+}
+
 func normalizeManifest(manifest string) string {
 	// The full grammar for manifests can be found here:
 	// https://docs.oracle.com/javase/8/docs/technotes/guides/jar/jar.html#JARManifest
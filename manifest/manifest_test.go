@@ -14,12 +14,13 @@
  * limitations under the License.
  */
 
-package manifest
+package manifest_test
 
 import (
 	"path/filepath"
 	"testing"
 
+	"github.com/cloudfoundry/libcfbuildpack/v2/manifest"
 	"github.com/cloudfoundry/libcfbuildpack/v2/test"
 	"github.com/onsi/gomega"
 	"github.com/sclevine/spec"
@@ -38,7 +39,7 @@ func TestManifest(t *testing.T) {
 		})
 
 		it("returns empty manifest if file doesn't exist", func() {
-			m, err := NewManifest(f.Detect.Application, f.Detect.Logger)
+			m, err := manifest.NewManifest(f.Detect.Application, f.Detect.Logger)
 
 			g.Expect(err).NotTo(gomega.HaveOccurred())
 			g.Expect(m.Len()).To(gomega.Equal(0))
@@ -47,7 +48,7 @@ func TestManifest(t *testing.T) {
 		it("returns populated manifest if file exists", func() {
 			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"), "test-key=test-value")
 
-			m, err := NewManifest(f.Detect.Application, f.Detect.Logger)
+			m, err := manifest.NewManifest(f.Detect.Application, f.Detect.Logger)
 
 			g.Expect(err).NotTo(gomega.HaveOccurred())
 
@@ -71,7 +72,7 @@ Created-By: Maven Archiver 3.4.0
 Main-Class: org.springframework.boot.loader.JarLauncher
 `)
 
-			m, err := NewManifest(f.Detect.Application, f.Detect.Logger)
+			m, err := manifest.NewManifest(f.Detect.Application, f.Detect.Logger)
 
 			g.Expect(err).NotTo(gomega.HaveOccurred())
 
@@ -80,5 +81,28 @@ Main-Class: org.springframework.boot.loader.JarLauncher
 			g.Expect(k).To(gomega.Equal("org.springframework.samples.petclinic.PetClinicApplication"))
 
 		})
+
+		it("returns empty manifest if JAR has no manifest", func() {
+			jar := filepath.Join(f.Detect.Application.Root, "test.jar")
+			test.WriteJAR(t, jar, map[string]string{"test-file": "test-content"})
+
+			m, err := manifest.NewManifestFromJAR(jar, f.Detect.Logger)
+
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(m.Len()).To(gomega.Equal(0))
+		})
+
+		it("returns populated manifest if JAR has manifest", func() {
+			jar := filepath.Join(f.Detect.Application.Root, "test.jar")
+			test.WriteJAR(t, jar, map[string]string{"META-INF/MANIFEST.MF": "Implementation-Title: test-titl\r\n e\r\n"})
+
+			m, err := manifest.NewManifestFromJAR(jar, f.Detect.Logger)
+
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			k, ok := m.Get("Implementation-Title")
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(k).To(gomega.Equal("test-title"))
+		})
 	}, spec.Report(report.Terminal{}))
 }
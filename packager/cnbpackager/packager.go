@@ -29,6 +29,7 @@ import (
 	"sort"
 	"strings"
 	templ "text/template"
+	"time"
 
 	"github.com/Masterminds/semver"
 	"github.com/heroku/color"
@@ -99,6 +100,14 @@ func (p Packager) Create(cache bool) error {
 		return err
 	}
 
+	if err := p.buildpack.Validate(); err != nil {
+		return err
+	}
+
+	if err := p.evaluateLicenses(); err != nil {
+		return err
+	}
+
 	includedFiles, err := p.buildpack.IncludeFiles()
 	if err != nil {
 		return err
@@ -149,6 +158,31 @@ func insertTemplateVersion(bpDir, version string) error {
 	return template.Execute(file, v)
 }
 
+func (p Packager) evaluateLicenses() error {
+	policy, err := p.buildpack.LicensePolicy()
+	if err != nil {
+		return err
+	}
+
+	deps, err := p.buildpack.Dependencies()
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, dep := range deps {
+		if err := policy.Evaluate(dep, p.logger); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+
+	return nil
+}
+
 func (p Packager) cacheDependencies() ([]pkgFile, error) {
 	var files []pkgFile
 
@@ -297,7 +331,16 @@ func (p Packager) Summary() (string, error) {
 		return "", err
 	}
 
+	if err := p.lifecycleSummary(&out); err != nil {
+		return "", err
+	}
+
 	p.defaultsSummary(&out)
+
+	if err := p.configurationsSummary(&out); err != nil {
+		return "", err
+	}
+
 	p.stacksSummary(&out)
 
 	return out, nil
@@ -306,9 +349,12 @@ func (p Packager) Summary() (string, error) {
 func (p Packager) depsSummary(out *string) error {
 
 	type depKey struct {
-		Idx     int
-		ID      string
-		Version string
+		Idx             int
+		ID              string
+		Version         string
+		SemanticVersion string
+		OS              string
+		Arch            string
 	}
 
 	bpMetadata := p.buildpack.Metadata
@@ -317,9 +363,7 @@ func (p Packager) depsSummary(out *string) error {
 		return nil
 	}
 
-	*out = "\nPackaged binaries:\n\n"
-	*out += "| name | version | stacks |\n|-|-|-|\n"
-
+	platforms := false
 	depMap := map[depKey]buildpack.Stacks{}
 	for _, d := range deps {
 		dep, err := buildpack.NewDependency(d)
@@ -327,8 +371,18 @@ func (p Packager) depsSummary(out *string) error {
 			return err
 		}
 		depKey := depKey{
-			ID:      dep.ID,
-			Version: dep.Version.Version.String(),
+			ID:              dep.ID,
+			Version:         dep.Version.Version.String(),
+			SemanticVersion: dep.Version.Version.String(),
+			OS:              dep.OS,
+			Arch:            dep.Arch,
+		}
+		if dep.OS != "" || dep.Arch != "" {
+			platforms = true
+		}
+		if dep.Version.Original() != dep.Version.Version.Original() {
+			// versions interpreted by a VersionScheme are shown in their original form
+			depKey.Version = dep.Version.Original()
 		}
 		if _, ok := depMap[depKey]; !ok {
 			depMap[depKey] = dep.Stacks
@@ -346,31 +400,114 @@ func (p Packager) depsSummary(out *string) error {
 		if alph < 0 {
 			return true
 		} else if alph == 0 {
-			versionI, err := semver.NewVersion(depKeyArray[i].Version)
+			versionI, err := semver.NewVersion(depKeyArray[i].SemanticVersion)
 			if err != nil {
 				return false
 			}
-			versionJ, err := semver.NewVersion(depKeyArray[j].Version)
+			versionJ, err := semver.NewVersion(depKeyArray[j].SemanticVersion)
 			if err != nil {
 				return false
 			}
-			return versionI.GreaterThan(versionJ)
+			if !versionI.Equal(versionJ) {
+				return versionI.GreaterThan(versionJ)
+			}
+			if depKeyArray[i].OS != depKeyArray[j].OS {
+				return depKeyArray[i].OS < depKeyArray[j].OS
+			}
+			return depKeyArray[i].Arch < depKeyArray[j].Arch
 		}
 		return false
 	})
 
+	*out = "\nPackaged binaries:\n\n"
+	if platforms {
+		*out += "| name | version | stacks | os | arch |\n|-|-|-|-|-|\n"
+	} else {
+		*out += "| name | version | stacks |\n|-|-|-|\n"
+	}
+
 	for _, dKey := range depKeyArray {
 		stacks := depMap[dKey]
 		stackStringArray := []string{}
 		for _, stack := range stacks {
-			stackStringArray = append(stackStringArray, string(stack))
+			if stack == buildpack.AnyStack {
+				stackStringArray = append(stackStringArray, "all stacks")
+			} else {
+				stackStringArray = append(stackStringArray, string(stack))
+			}
+		}
+		if platforms {
+			*out += fmt.Sprintf("| %s | %s | %s | %s | %s |\n", dKey.ID, dKey.Version, strings.Join(stackStringArray, ", "),
+				platformValue(dKey.OS), platformValue(dKey.Arch))
+		} else {
+			*out += fmt.Sprintf("| %s | %s | %s |\n", dKey.ID, dKey.Version, strings.Join(stackStringArray, ", "))
 		}
-		*out += fmt.Sprintf("| %s | %s | %s |\n", dKey.ID, dKey.Version, strings.Join(stackStringArray, ", "))
 	}
 
 	return nil
 }
 
+func platformValue(value string) string {
+	if value == "" {
+		return "all"
+	}
+
+	return value
+}
+
+func (p Packager) lifecycleSummary(out *string) error {
+	deps, err := p.buildpack.Dependencies()
+	if err != nil {
+		return err
+	}
+
+	var lifecycle buildpack.Dependencies
+	for _, d := range deps {
+		if d.DeprecationDate != nil || d.EOL != nil || len(d.CVEs) > 0 {
+			lifecycle = append(lifecycle, d)
+		}
+	}
+
+	if len(lifecycle) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(lifecycle, func(i, j int) bool {
+		return lifecycleDate(lifecycle[i]).Before(lifecycleDate(lifecycle[j]))
+	})
+
+	*out += "\nDeprecations:\n\n"
+	*out += "| name | version | deprecation date | eol | cves |\n|-|-|-|-|-|\n"
+	for _, d := range lifecycle {
+		*out += fmt.Sprintf("| %s | %s | %s | %s | %s |\n", d.ID, d.Version.Original(),
+			formatDate(d.DeprecationDate), formatDate(d.EOL), strings.Join(d.CVEs, ", "))
+	}
+
+	return nil
+}
+
+// lifecycleDate returns the earliest of a dependency's deprecation date and end-of-life, or the latest possible time
+// if it has neither.
+func lifecycleDate(dependency buildpack.Dependency) time.Time {
+	t := time.Unix(1<<62, 0)
+
+	for _, d := range []*time.Time{dependency.DeprecationDate, dependency.EOL} {
+		if d != nil && d.Before(t) {
+			t = *d
+		}
+	}
+
+	return t
+}
+
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+
+	return date.Format("2006-01-02")
+}
+
 func (p Packager) defaultsSummary(out *string) {
 	bpMetadata := p.buildpack.Metadata
 	defaults, ok := bpMetadata[buildpack.DefaultVersions].(map[string]interface{})
@@ -387,6 +524,25 @@ func (p Packager) defaultsSummary(out *string) {
 	}
 }
 
+func (p Packager) configurationsSummary(out *string) error {
+	configurations, err := p.buildpack.Configurations()
+	if err != nil {
+		return err
+	}
+
+	if len(configurations) == 0 {
+		return nil
+	}
+
+	*out += "\nConfiguration:\n\n"
+	*out += "| environment variable | default | build | launch | description |\n|-|-|-|-|-|\n"
+	for _, c := range configurations {
+		*out += fmt.Sprintf("| $%s | %s | %t | %t | %s |\n", c.Name, c.Default, c.Build, c.Launch, c.Description)
+	}
+
+	return nil
+}
+
 func (p Packager) stacksSummary(out *string) {
 	if len(p.buildpack.Stacks) < 1 {
 		return
@@ -401,4 +557,21 @@ Supported stacks:
 	for _, stack := range p.buildpack.Stacks {
 		*out += fmt.Sprintf("| %s |\n", stack.ID)
 	}
+
+	parents, ok := p.buildpack.Metadata[buildpack.StackParents].(map[string]interface{})
+	if !ok || len(parents) == 0 {
+		return
+	}
+
+	var names []string
+	for name := range parents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	*out += "\nStack parents:\n\n"
+	*out += "| name | parent |\n|-|-|\n"
+	for _, name := range names {
+		*out += fmt.Sprintf("| %s | %s |\n", name, parents[name])
+	}
 }
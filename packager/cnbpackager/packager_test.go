@@ -69,6 +69,9 @@ stacks = ["stack-id"]
 uri = "file://%s"
 version = "1.0.0"
 
+[[metadata.dependencies.licenses]]
+type = "Apache-2.0"
+
 [[stacks]]
 id = 'stack-id'`, depSHA, depFile)
 
@@ -152,6 +155,45 @@ id = 'stack-id'`, depSHA, depFile)
 		})
 	})
 
+	when("invalid metadata", func() {
+		it.Before(func() {
+			gomega.Expect(ioutil.WriteFile(filepath.Join(cnbDir, "buildpack.toml"), []byte(buildpackTOML+`
+
+[metadata.default-versions]
+dependency-id = "2.*"
+`), 0666)).To(gomega.Succeed())
+
+			pkgr, err = New(cnbDir, outputDir, "", cacheDir)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		})
+
+		it("Create fails when the buildpack metadata is invalid", func() {
+			gomega.Expect(pkgr.Create(false)).To(gomega.MatchError("invalid buildpack metadata:\ndefault-versions.dependency-id: 2.* does not match any dependency"))
+			gomega.Expect(filepath.Join(outputDir, "buildpack.toml")).NotTo(gomega.BeAnExistingFile())
+		})
+	})
+
+	when("license policy", func() {
+		it.Before(func() {
+			gomega.Expect(ioutil.WriteFile(filepath.Join(cnbDir, "buildpack.toml"), []byte(buildpackTOML+`
+
+[metadata.license-policy]
+deny = ["GPL-3.0-only"]
+
+[[metadata.dependencies.licenses]]
+type = "GPL-3.0-only"
+`), 0666)).To(gomega.Succeed())
+
+			pkgr, err = New(cnbDir, outputDir, "", cacheDir)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		})
+
+		it("Create fails when a dependency violates the license policy", func() {
+			gomega.Expect(pkgr.Create(false)).To(gomega.MatchError("dependency-id 1.0.0 violates the license policy:\nlicense GPL-3.0-only is denied"))
+			gomega.Expect(filepath.Join(outputDir, "buildpack.toml")).NotTo(gomega.BeAnExistingFile())
+		})
+	})
+
 	when("archiving", func() {
 		it.Before(func() {
 			fakeCnbDir := filepath.Join("testdata", "archive-testdata", "fake-cnb")
@@ -242,6 +284,120 @@ Supported stacks:
 			gomega.Expect(summary).To(gomega.Equal(solution))
 		})
 
+		it("has deprecations", func() {
+			fakeCnbDir := filepath.Join("testdata", "summary-testdata", "fake-cnb-with-deprecations")
+			pkgr, err = New(fakeCnbDir, "", "", "")
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			solution := `
+Packaged binaries:
+
+| name | version | stacks |
+|-|-|-|
+| dep1 | 4.5.6 | stack1 |
+| dep2 | 7.8.9 | stack1 |
+| dep3 | 1.2.3 | stack1 |
+
+Deprecations:
+
+| name | version | deprecation date | eol | cves |
+|-|-|-|-|-|
+| dep3 | 1.2.3 | 2025-03-01 |  |  |
+| dep1 | 4.5.6 | 2030-06-01 | 2030-01-01 |  |
+| dep2 | 7.8.9 |  |  | CVE-2020-0001 |
+
+Supported stacks:
+
+| name |
+|-|
+| stack1 |
+`
+
+			summary, err := pkgr.Summary()
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(summary).To(gomega.Equal(solution))
+		})
+
+		it("has configurations", func() {
+			fakeCnbDir := filepath.Join("testdata", "summary-testdata", "fake-cnb-with-configurations")
+			pkgr, err = New(fakeCnbDir, "", "", "")
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			solution := `
+Configuration:
+
+| environment variable | default | build | launch | description |
+|-|-|-|-|-|
+| $BP_FAKE_VERSION | 1.* | true | false | the version of fake to install |
+| $BP_FAKE_DEBUG | false | false | true | whether fake starts with debugging enabled |
+
+Supported stacks:
+
+| name |
+|-|
+| stack1 |
+`
+
+			summary, err := pkgr.Summary()
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(summary).To(gomega.Equal(solution))
+		})
+
+		it("has wildcard stacks and stack parents", func() {
+			fakeCnbDir := filepath.Join("testdata", "summary-testdata", "fake-cnb-with-stack-parents")
+			pkgr, err = New(fakeCnbDir, "", "", "")
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			solution := `
+Packaged binaries:
+
+| name | version | stacks |
+|-|-|-|
+| dep1 | 4.5.6 | all stacks |
+| dep2 | 7.8.9 | stack1 |
+
+Supported stacks:
+
+| name |
+|-|
+| stack1 |
+| stack2 |
+
+Stack parents:
+
+| name | parent |
+|-|-|
+| stack2 | stack1 |
+| stack3 | stack1 |
+`
+
+			summary, err := pkgr.Summary()
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(summary).To(gomega.Equal(solution))
+		})
+
+		it("has platform specific dependencies", func() {
+			fakeCnbDir := filepath.Join("testdata", "summary-testdata", "fake-cnb-with-platforms")
+			pkgr, err = New(fakeCnbDir, "", "", "")
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			solution := `
+Packaged binaries:
+
+| name | version | stacks | os | arch |
+|-|-|-|-|-|
+| dep1 | 4.5.6 | stack1 | linux | amd64 |
+| dep1 | 4.5.6 | stack1 | linux | arm64 |
+| dep2 | 7.8.9 | stack1 | all | all |
+
+Supported stacks:
+
+| name |
+|-|
+| stack1 |
+`
+
+			summary, err := pkgr.Summary()
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(summary).To(gomega.Equal(solution))
+		})
+
 		it("does not have any dependencies", func() {
 			fakeCnbDir := filepath.Join("testdata", "summary-testdata", "fake-cnb-without-dependencies")
 			pkgr, err = New(fakeCnbDir, "", "", "")
@@ -139,7 +139,7 @@ func (f *BuildFactory) addDependency(dependency buildpack.Dependency) {
 	metadata[buildpack.DependenciesMetadata] = append(dependencies, map[string]interface{}{
 		"id":       dependency.ID,
 		"name":     dependency.Name,
-		"version":  dependency.Version.Version.Original(),
+		"version":  dependency.Version.Original(),
 		"uri":      dependency.URI,
 		"sha256":   dependency.SHA256,
 		"stacks":   stacks,
@@ -188,6 +188,7 @@ func NewBuildFactory(t *testing.T) *BuildFactory {
 	}
 	f.Build.Buildpack.Info.Version = "1.0"
 	f.Build.Buildpack.Root = filepath.Join(root, "buildpack")
+	f.Build.Buildpack.BuildpackYAML = filepath.Join(f.Build.Application.Root, "buildpack.yml")
 	f.Build.Layers = layers.NewLayers(
 		bpLayers.Layers{Root: filepath.Join(root, "layers")},
 		bpLayers.Layers{Root: filepath.Join(root, "buildpack-cache")}, f.Build.Buildpack, logger.Logger{})
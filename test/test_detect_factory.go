@@ -70,6 +70,7 @@ func NewDetectFactory(t *testing.T) *DetectFactory {
 	}
 	f.Detect.Buildpack.Info.Version = "1.0"
 	f.Detect.Buildpack.Root = filepath.Join(root, "buildpack")
+	f.Detect.Buildpack.BuildpackYAML = filepath.Join(f.Detect.Application.Root, "buildpack.yml")
 	f.Detect.Platform.Root = filepath.Join(root, "platform")
 	f.Detect.Runner = runner
 	f.Detect.Services = services.Services{Services: bp.Services{}}